@@ -16,7 +16,13 @@ const (
 // AppConfig 应用基础配置结构
 type AppConfig struct {
 	Name string `mapstructure:"name" json:"name" yaml:"name"`
-	Env  string `mapstructure:"env" json:"env" yaml:"env"`
+	Env  string `mapstructure:"env" json:"env" yaml:"env" validate:"omitempty,oneof=dev test prod"`
+
+	// Watch 是否监听配置文件变更并热加载
+	Watch bool `mapstructure:"watch" json:"watch" yaml:"watch"`
+
+	// Remote 远程配置源
+	Remote []RemoteConfig `mapstructure:"remote" json:"remote" yaml:"remote" validate:"dive"`
 }
 
 // GetAppConf 获取应用基础配置
@@ -34,14 +40,25 @@ func AppName() string {
 	return Pick().GetString("app.name")
 }
 
+// AppWatch 获取配置的应用是否开启配置热加载
+func AppWatch() bool {
+	return Pick().GetBool("app.watch")
+}
+
 // AppEnv 获取配置的应用Env
 func AppEnv() string {
-	env := Pick().GetString("app.env")
-	if env == "" {
-		env = AppEnvDev
-	}
+	return normalizeEnv(Pick().GetString("app.env"))
+}
+
+// normalizeEnv 规范化应用Env 未知取值一律视为dev
+func normalizeEnv(env string) string {
 	if env != AppEnvTest && env != AppEnvProd {
-		env = AppEnvDev
+		return AppEnvDev
 	}
 	return env
 }
+
+// isAppEnv 判断是否为合法的应用Env
+func isAppEnv(env string) bool {
+	return env == AppEnvDev || env == AppEnvTest || env == AppEnvProd
+}
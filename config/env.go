@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix 环境变量覆盖配置的统一前缀
+// 格式: {EnvPrefix}_{SCOPE}__{KEY}__{SUBKEY}... 例如 MYGO_CONFIG__DB__PASSWORD 覆盖 config.yaml[db.password]
+const EnvPrefix = "MYGO"
+
+const envKeySeparator = "__"
+
+// applyEnvOverlay 将匹配指定scope的环境变量深度合并进配置实例
+func applyEnvOverlay(scope string, v *viper.Viper) error {
+	overlay := envOverlay(scope, os.Environ())
+	if len(overlay) == 0 {
+		return nil
+	}
+	return v.MergeConfigMap(overlay)
+}
+
+// envOverlay 从环境变量列表中解析出指定scope的覆盖配置
+func envOverlay(scope string, environ []string) map[string]any {
+	prefix := EnvPrefix + "_" + strings.ToUpper(scope) + envKeySeparator
+
+	// 按变量名排序 保证覆盖顺序确定
+	pairs := make([][2]string, 0)
+	for _, kv := range environ {
+		k, val, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(strings.ToUpper(k), prefix) {
+			continue
+		}
+		pairs = append(pairs, [2]string{k[len(prefix):], val})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0]
+	})
+
+	overlay := map[string]any{}
+	for _, pair := range pairs {
+		path := strings.Split(strings.ToLower(pair[0]), envKeySeparator)
+		if !validEnvPath(path) {
+			continue
+		}
+		setPath(overlay, path, pair[1])
+	}
+	return overlay
+}
+
+func validEnvPath(path []string) bool {
+	for _, p := range path {
+		if p == "" {
+			return false
+		}
+	}
+	return len(path) != 0
+}
+
+// setPath 在嵌套map中按路径设置值 路径中间节点非map时会被覆盖为map
+func setPath(m map[string]any, path []string, value any) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvOverlay(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		environ []string
+		want    map[string]any
+	}{
+		{
+			name:    "嵌套键",
+			scope:   "config",
+			environ: []string{"MYGO_CONFIG__DB__PASSWORD=secret", "MYGO_CONFIG__APP__NAME=demo", "PATH=/usr/bin"},
+			want:    map[string]any{"db": map[string]any{"password": "secret"}, "app": map[string]any{"name": "demo"}},
+		},
+		{
+			name:    "键名大小写不敏感",
+			scope:   "redis",
+			environ: []string{"mygo_redis__Default__Addrs=127.0.0.1:6379"},
+			want:    map[string]any{"default": map[string]any{"addrs": "127.0.0.1:6379"}},
+		},
+		{
+			name:    "值中包含等号",
+			scope:   "config",
+			environ: []string{"MYGO_CONFIG__DB__DSN=user:pass@tcp(db)/app?charset=utf8"},
+			want:    map[string]any{"db": map[string]any{"dsn": "user:pass@tcp(db)/app?charset=utf8"}},
+		},
+		{
+			name:    "深层键覆盖同名叶子",
+			scope:   "config",
+			environ: []string{"MYGO_CONFIG__LOG=debug", "MYGO_CONFIG__LOG__LEVEL=info"},
+			want:    map[string]any{"log": map[string]any{"level": "info"}},
+		},
+		{
+			name:    "忽略其他scope与空路径",
+			scope:   "config",
+			environ: []string{"MYGO_CONFIGX__A=1", "MYGO_REDIS__A=1", "MYGO_CONFIG__=1", "MYGO_CONFIG__A____B=1"},
+			want:    map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := envOverlay(tt.scope, tt.environ); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("envOverlay() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBootMapEnvOverlay(t *testing.T) {
+	defer resetForTest()()
+	t.Setenv("MYGO_CONFIG__DB__PASSWORD", "from-env")
+	t.Setenv("MYGO_REDIS__DEFAULT__DB", "2")
+
+	err := BootMap(map[string]map[string]any{
+		"config": {"db": map[string]any{"host": "127.0.0.1", "password": "from-file"}},
+		"redis":  {"default": map[string]any{"db": 0}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Pick().GetString("db.password"); got != "from-env" {
+		t.Fatalf("db.password = %q, want from-env", got)
+	}
+	if got := Pick().GetString("db.host"); got != "127.0.0.1" {
+		t.Fatalf("db.host = %q, want 127.0.0.1", got)
+	}
+	if got := Pick("redis").GetInt("default.db"); got != 2 {
+		t.Fatalf("redis default.db = %d, want 2", got)
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"io"
+
+	"github.com/samber/do"
+)
+
+// resetForTest 重置配置状态与do默认容器 并屏蔽配置输出
+func resetForTest() (restore func()) {
+	prevInjector, prevOutput := do.DefaultInjector, output
+	do.DefaultInjector = do.New()
+	output = io.Discard
+	restoreConfig := Reset()
+	return func() {
+		restoreConfig()
+		do.DefaultInjector, output = prevInjector, prevOutput
+	}
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	includeKey = "include"
+	refKey     = "$ref"
+)
+
+// decoders 配置文件解码器 在viper内置格式基础上补充ini
+var decoders = decoderRegistry{viper.NewCodecRegistry()}
+
+// readFile 读取单个配置文件并展开include与$ref 返回配置内容与实际读取的文件列表 (按合并顺序)
+//   - 顶层 include: [a.yaml, redis.d/*.yaml]  先按顺序合并被引入的文件 再合并当前文件自身内容
+//   - 任意层级 key: {$ref: db/main.yaml}       以被引用文件的内容作为该key的值 同级的其他键覆盖引用内容
+//
+// 路径均相对于当前文件所在目录 支持glob 引用存在环时报错
+// 位于配置目录中的被引用文件仅作为片段合并 不作为独立scope加载
+func readFile(file, typ string, stack []string) (map[string]any, []string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if slices.Contains(stack, abs) {
+		return nil, nil, fmt.Errorf("配置文件引用存在环: %v -> %s", stack, abs)
+	}
+	stack = append(stack, abs)
+
+	fv := viper.NewWithOptions(viper.WithDecoderRegistry(decoders))
+	fv.SetConfigFile(file)
+	fv.SetConfigType(typ)
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, nil, err
+	}
+	content := fv.AllSettings()
+	dir := filepath.Dir(file)
+
+	// 展开include
+	merged := map[string]any{}
+	var files []string
+	if inc, ok := content[includeKey]; ok {
+		delete(content, includeKey)
+		patterns, err := toStrings(inc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("配置文件[%s]的include格式错误: %w", file, err)
+		}
+		for _, pattern := range patterns {
+			incFiles, err := expand(dir, pattern)
+			if err != nil {
+				return nil, nil, fmt.Errorf("配置文件[%s]的include[%s]错误: %w", file, pattern, err)
+			}
+			for _, f := range incFiles {
+				m, fs, err := readInclude(f, stack)
+				if err != nil {
+					return nil, nil, err
+				}
+				deepMerge(merged, m)
+				files = append(files, fs...)
+			}
+		}
+	}
+
+	// 展开$ref
+	content, refFiles, err := resolveRefs(dir, content, stack)
+	if err != nil {
+		return nil, nil, fmt.Errorf("配置文件[%s]的$ref错误: %w", file, err)
+	}
+	files = append(files, refFiles...)
+
+	deepMerge(merged, content)
+	files = append(files, file)
+	return merged, files, nil
+}
+
+// includeTargets 收集各配置层通过include与$ref引用的文件 (绝对路径)
+func includeTargets(scopeLayers map[string][]layer) (map[string]bool, error) {
+	targets := map[string]bool{}
+	for _, ls := range scopeLayers {
+		for _, l := range ls {
+			_, files, err := readFile(l.file, l.typ, nil)
+			if err != nil {
+				return nil, fmt.Errorf("读取配置文件[%s]错误: %w", l.file, err)
+			}
+			// 最后一个为文件自身
+			for _, f := range files[:len(files)-1] {
+				abs, err := filepath.Abs(f)
+				if err != nil {
+					return nil, err
+				}
+				targets[abs] = true
+			}
+		}
+	}
+	return targets, nil
+}
+
+// readInclude 按扩展名读取被引入的配置文件
+func readInclude(file string, stack []string) (map[string]any, []string, error) {
+	typ, ok := extMap[filepath.Ext(file)]
+	if !ok {
+		return nil, nil, fmt.Errorf("不支持的配置文件格式[%s]", file)
+	}
+	return readFile(file, typ, stack)
+}
+
+// resolveRefs 递归展开map中的$ref
+func resolveRefs(dir string, m map[string]any, stack []string) (map[string]any, []string, error) {
+	var files []string
+	out := map[string]any{}
+	if ref, ok := m[refKey]; ok {
+		path, ok := ref.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("$ref的值应为文件路径, 实际为[%v]", ref)
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		refContent, fs, err := readInclude(path, stack)
+		if err != nil {
+			return nil, nil, err
+		}
+		deepMerge(out, refContent)
+		files = append(files, fs...)
+	}
+	for k, v := range m {
+		if k == refKey {
+			continue
+		}
+		if sub, ok := v.(map[string]any); ok {
+			r, fs, err := resolveRefs(dir, sub, stack)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, fs...)
+			v = r
+		}
+		if sub, ok := v.(map[string]any); ok {
+			if exist, ok := out[k].(map[string]any); ok {
+				deepMerge(exist, sub)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out, files, nil
+}
+
+// expand 展开相对于dir的glob路径 结果有序
+func expand(dir, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("未匹配到任何文件")
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func toStrings(v any) ([]string, error) {
+	switch x := v.(type) {
+	case string:
+		return []string{x}, nil
+	case []any:
+		out := make([]string, 0, len(x))
+		for _, item := range x {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("应为字符串, 实际为[%v]", item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("应为字符串或字符串列表, 实际为[%v]", v)
+}
+
+// deepMerge 将src深度合并进dst 同名非map值以src为准
+func deepMerge(dst, src map[string]any) {
+	for k, sv := range src {
+		if sm, ok := sv.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				deepMerge(dm, sm)
+				continue
+			}
+		}
+		dst[k] = sv
+	}
+}
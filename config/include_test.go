@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles 在dir下写入测试配置文件
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "include按顺序合并 自身内容最后合并",
+			files: map[string]string{
+				"config.yaml":         "include: [redis.d/*.yaml, base.json]\nname: app\nredis:\n  a:\n    db: 3\n",
+				"redis.d/a.yaml":      "redis:\n  a:\n    addr: a:6379\n    db: 1\n",
+				"redis.d/b.yml":       "redis:\n  b:\n    addr: b:6379\n",
+				"redis.d/ignore.toml": "x = 1\n",
+				"base.json":           `{"name": "base", "debug": true}`,
+			},
+			want: map[string]any{
+				"name":  "app",
+				"debug": true,
+				"redis": map[string]any{
+					"a": map[string]any{"addr": "a:6379", "db": 3},
+				},
+			},
+		},
+		{
+			name: "$ref作为key的值 同级键覆盖引用内容",
+			files: map[string]string{
+				"config.yaml": "db:\n  main:\n    $ref: db/main.ini\n    port: 3307\n",
+				"db/main.ini": "host = 127.0.0.1\nport = 3306\n",
+			},
+			want: map[string]any{
+				"db": map[string]any{"main": map[string]any{"host": "127.0.0.1", "port": 3307}},
+			},
+		},
+		{
+			name: "include存在环",
+			files: map[string]string{
+				"config.yaml": "include: a.yaml\n",
+				"a.yaml":      "include: b.yaml\n",
+				"b.yaml":      "include: a.yaml\n",
+			},
+			wantErr: "配置文件引用存在环",
+		},
+		{
+			name: "$ref引用自身",
+			files: map[string]string{
+				"config.yaml": "db:\n  $ref: config.yaml\n",
+			},
+			wantErr: "配置文件引用存在环",
+		},
+		{
+			name: "include未匹配到文件",
+			files: map[string]string{
+				"config.yaml": "include: missing/*.yaml\n",
+			},
+			wantErr: "未匹配到任何文件",
+		},
+		{
+			name: "include格式错误",
+			files: map[string]string{
+				"config.yaml": "include: {a: b}\n",
+			},
+			wantErr: "include格式错误",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			got, _, err := readFile(filepath.Join(dir, "config.yaml"), "yaml", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readFile() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("readFile() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// decoderRegistry 在viper内置解码器基础上补充ini格式
+type decoderRegistry struct {
+	*viper.DefaultCodecRegistry
+}
+
+func (r decoderRegistry) Decoder(format string) (viper.Decoder, error) {
+	if strings.ToLower(format) == "ini" {
+		return iniDecoder{}, nil
+	}
+	return r.DefaultCodecRegistry.Decoder(format)
+}
+
+// iniDecoder 简易ini解码器
+//   - ; 或 # 开头的行为注释
+//   - [a.b] 节名按.拆分为嵌套层级 首个节之前的键位于顶层
+//   - key = value 值两侧的引号会被去除
+type iniDecoder struct{}
+
+func (iniDecoder) Decode(b []byte, v map[string]any) error {
+	section := v
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("ini第%d行节名格式错误: %s", n, line)
+			}
+			section = v
+			for _, name := range strings.Split(strings.TrimSpace(line[1:len(line)-1]), ".") {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if name == "" {
+					return fmt.Errorf("ini第%d行节名格式错误: %s", n, line)
+				}
+				sub, ok := section[name].(map[string]any)
+				if !ok {
+					sub = map[string]any{}
+					section[name] = sub
+				}
+				section = sub
+			}
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("ini第%d行缺少'=': %s", n, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			return fmt.Errorf("ini第%d行键名为空: %s", n, line)
+		}
+		section[key] = unquote(strings.TrimSpace(val))
+	}
+	return scanner.Err()
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
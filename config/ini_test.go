@@ -0,0 +1,59 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIniDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "节名按点拆分为嵌套层级",
+			input: `; 注释
+name = app
+# 注释
+[DB.Main]
+Host = 127.0.0.1
+password = "p;ss=word"
+[db.replica]
+host = '10.0.0.2'
+`,
+			want: map[string]any{
+				"name": "app",
+				"db": map[string]any{
+					"main":    map[string]any{"host": "127.0.0.1", "password": "p;ss=word"},
+					"replica": map[string]any{"host": "10.0.0.2"},
+				},
+			},
+		},
+		{name: "空内容", input: "\n\n", want: map[string]any{}},
+		{name: "节名未闭合", input: "[db\nhost = a\n", wantErr: "ini第1行节名格式错误"},
+		{name: "空节名", input: "[db..main]\n", wantErr: "ini第1行节名格式错误"},
+		{name: "缺少等号", input: "a = 1\nhost\n", wantErr: "ini第2行缺少'='"},
+		{name: "空键名", input: " = 1\n", wantErr: "ini第1行键名为空"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]any{}
+			err := iniDecoder{}.Decode([]byte(tt.input), got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("Decode() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
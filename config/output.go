@@ -0,0 +1,14 @@
+package config
+
+import (
+	"io"
+	"os"
+)
+
+// output 配置加载与变更提示信息的输出位置
+var output io.Writer = os.Stdout
+
+// SetOutput 设置配置加载与变更提示信息的输出位置 默认为标准输出
+func SetOutput(w io.Writer) {
+	output = w
+}
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/samber/do"
 	"github.com/spf13/viper"
@@ -16,44 +22,113 @@ const (
 
 var extMap = map[string]string{
 	".yaml": "yaml",
-	// ".toml": "toml",
-	// ".ini":  "ini",
-	// ".yml":  "yml",
-	// ".json": "json",
+	".yml":  "yaml",
+	".toml": "toml",
+	".ini":  "ini",
+	".json": "json",
 }
 
+// layer 单个配置文件层
+type layer struct {
+	file string
+	typ  string
+}
+
+// loaded 已加载的scope配置实例及其实际读取的文件 (含include) 按合并顺序排列
+type loaded struct {
+	v     *viper.Viper
+	files []string
+}
+
+var (
+	// bootPath Boot时指定的配置目录
+	bootPath string
+	// layers 记录各scope实际合并的配置文件 按合并顺序排列
+	layers = map[string][]string{}
+	mu     sync.RWMutex
+)
+
 // Boot 预加载指定目录下全部配置文件实例
+// 同一scope按以下顺序深度合并 (后者覆盖前者):
+//  1. {path}/{scope}.yaml          基础配置
+//  2. {path}/{scope}.{env}.yaml    环境配置
+//  3. {path}/{env}/{scope}.yaml    环境覆盖目录
+//  4. app.remote                   远程配置源 (见RemoteConfig)
+//  5. MYGO_{SCOPE}__{KEY}          环境变量
+//
+// 支持yaml/yml/toml/json/ini格式, 同一scope存在多个格式的文件时视为冲突
+// 配置文件可通过include与$ref拆分 详见readFile
+// 合并完成后解析配置值中的${env:NAME}、${file:/path}引用与ENC(...)加密值
+// 其中env由基础配置config.yaml[app.env] (含环境变量覆盖) 决定
 func Boot(path string) error {
-	des, err := os.ReadDir(path)
+	scopeLayers, err := scan(path)
 	if err != nil {
-		return fmt.Errorf("读取开发框架配置目录[%s]错误: %w", path, err)
+		return err
 	}
-	// 挂载配置 ({path/xx.yaml})
-	for _, de := range des {
-		if de.IsDir() {
-			continue
+	instances, srcs, err := loadAll(scopeLayers)
+	if err != nil {
+		return err
+	}
+
+	// 挂载配置
+	mu.Lock()
+	defer mu.Unlock()
+	bootPath = path
+	for scope, l := range instances {
+		layers[scope] = l.files
+		do.ProvideNamedValue(nil, iocPrefix+scope, l.v)
+	}
+	commitRemotes(srcs)
+	return nil
+}
+
+// BootMap 以内存中的配置内容挂载scope实例 (scope -> 配置内容) 常用于测试
+// 不读取配置文件 与文件配置一致叠加环境变量覆盖并解析密钥引用与加密值 挂载后不支持Reload与Watch
+func BootMap(scopes map[string]map[string]any) error {
+	instances := make(map[string]*viper.Viper, len(scopes))
+	for scope, settings := range scopes {
+		v := viper.New()
+		if err := v.MergeConfigMap(copyMap(settings)); err != nil {
+			return fmt.Errorf("加载配置[%s]错误: %w", scope, err)
 		}
-		ext := filepath.Ext(de.Name())
-		file := de.Name()[:len(de.Name())-len(ext)]
-		ext2, ok := extMap[ext]
-		if !ok {
-			continue
+		if err := applyEnvOverlay(scope, v); err != nil {
+			return fmt.Errorf("合并配置[%s]环境变量覆盖错误: %w", scope, err)
 		}
-		do.ProvideNamed(nil, iocPrefix+file, func(injector *do.Injector) (*viper.Viper, error) {
-			v := viper.New()
-			v.SetConfigName(file)
-			v.SetConfigType(ext2)
-			v.AddConfigPath(path)
-			err = v.ReadInConfig()
-			if err != nil {
-				return nil, err
-			}
-			return v, nil
-		})
+		if err := resolveSecrets(v); err != nil {
+			return fmt.Errorf("解析配置[%s]密钥错误: %w", scope, err)
+		}
+		instances[scope] = v
+	}
+
+	// 挂载配置
+	mu.Lock()
+	defer mu.Unlock()
+	for scope, v := range instances {
+		layers[scope] = nil
+		do.ProvideNamedValue(nil, iocPrefix+scope, v)
 	}
 	return nil
 }
 
+// Layers 获取指定scope按合并顺序排列的配置文件列表
+func Layers(scope string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), layers[scope]...)
+}
+
+// Scopes 获取全部已挂载scope (有序)
+func Scopes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	scopes := make([]string, 0, len(layers))
+	for scope := range layers {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
 // Exist 判断指定scope实例是否挂载 (被Boot过) 且类型正确
 func Exist(scope string) bool {
 	_, err := do.InvokeNamed[*viper.Viper](nil, iocPrefix+scope)
@@ -68,3 +143,178 @@ func Pick(scopes ...string) *viper.Viper {
 	}
 	return do.MustInvokeNamed[*viper.Viper](nil, iocPrefix+scope)
 }
+
+// scan 扫描配置目录 按合并顺序组装各scope配置层
+func scan(path string) (map[string][]layer, error) {
+	base, envFiles, err := scanDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取开发框架配置目录[%s]错误: %w", path, err)
+	}
+
+	// 确定应用环境
+	env, err := detectEnv(base[defaultScope])
+	if err != nil {
+		return nil, fmt.Errorf("读取应用环境配置错误: %w", err)
+	}
+
+	// 环境覆盖目录 ({path}/{env}/xx.yaml)
+	overlay, _, err := scanDir(filepath.Join(path, env))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("读取开发框架环境配置目录[%s]错误: %w", filepath.Join(path, env), err)
+	}
+
+	scopeLayers := map[string][]layer{}
+	for scope, l := range base {
+		scopeLayers[scope] = append(scopeLayers[scope], l)
+	}
+	for scope, l := range envFiles[env] {
+		scopeLayers[scope] = append(scopeLayers[scope], l)
+	}
+	for scope, l := range overlay {
+		scopeLayers[scope] = append(scopeLayers[scope], l)
+	}
+
+	// 被include或$ref引用的文件仅作为片段合并 不作为独立scope加载
+	targets, err := includeTargets(scopeLayers)
+	if err != nil {
+		return nil, err
+	}
+	for scope, ls := range scopeLayers {
+		ls = slices.DeleteFunc(ls, func(l layer) bool {
+			abs, err := filepath.Abs(l.file)
+			return err == nil && targets[abs]
+		})
+		if len(ls) == 0 {
+			delete(scopeLayers, scope)
+			continue
+		}
+		scopeLayers[scope] = ls
+	}
+	return scopeLayers, nil
+}
+
+// loadAll 加载全部scope配置实例 任一scope失败则整体失败
+// 远程配置源由默认scope的本地配置声明 仅存在远程配置的scope同样会被挂载
+func loadAll(scopeLayers map[string][]layer) (map[string]loaded, []*remoteSource, error) {
+	srcs, err := prepareRemotes(scopeLayers[defaultScope])
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载远程配置源错误: %w", err)
+	}
+	scopeRemotes := map[string][]*remoteSource{}
+	for _, src := range srcs {
+		scopeRemotes[src.conf.Scope] = append(scopeRemotes[src.conf.Scope], src)
+	}
+
+	instances := make(map[string]loaded, len(scopeLayers))
+	for scope, ls := range scopeLayers {
+		v, files, err := load(scope, ls, scopeRemotes[scope])
+		if err != nil {
+			return nil, nil, err
+		}
+		instances[scope] = loaded{v: v, files: files}
+	}
+	for scope, rs := range scopeRemotes {
+		if _, ok := instances[scope]; ok {
+			continue
+		}
+		v, files, err := load(scope, nil, rs)
+		if err != nil {
+			return nil, nil, err
+		}
+		instances[scope] = loaded{v: v, files: files}
+	}
+	return instances, srcs, nil
+}
+
+// scanDir 扫描目录下的配置文件
+// 返回基础配置 (scope -> 文件) 与环境配置 (env -> scope -> 文件)
+func scanDir(path string) (map[string]layer, map[string]map[string]layer, error) {
+	des, err := os.ReadDir(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := map[string]layer{}
+	envFiles := map[string]map[string]layer{}
+	for _, de := range des {
+		if de.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(de.Name())
+		typ, ok := extMap[ext]
+		if !ok {
+			continue
+		}
+		name := de.Name()[:len(de.Name())-len(ext)]
+		l := layer{file: filepath.Join(path, de.Name()), typ: typ}
+
+		// {scope}.{env}.yaml
+		if i := strings.LastIndex(name, "."); i > 0 && isAppEnv(name[i+1:]) {
+			env, scope := name[i+1:], name[:i]
+			if envFiles[env] == nil {
+				envFiles[env] = map[string]layer{}
+			}
+			if dup, ok := envFiles[env][scope]; ok {
+				return nil, nil, fmt.Errorf("配置scope[%s]的%s环境配置存在重复文件: %s, %s", scope, env, dup.file, l.file)
+			}
+			envFiles[env][scope] = l
+			continue
+		}
+		if dup, ok := base[name]; ok {
+			return nil, nil, fmt.Errorf("配置scope[%s]存在重复文件: %s, %s", name, dup.file, l.file)
+		}
+		base[name] = l
+	}
+	return base, envFiles, nil
+}
+
+// load 按顺序合并配置层、远程配置并叠加环境变量 返回配置实例与实际读取的文件列表 (远程配置源为其URL)
+func load(scope string, ls []layer, rs []*remoteSource) (*viper.Viper, []string, error) {
+	v := viper.New()
+	var files []string
+	for _, l := range ls {
+		m, fs, err := readFile(l.file, l.typ, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取配置文件[%s]错误: %w", l.file, err)
+		}
+		if err := v.MergeConfigMap(m); err != nil {
+			return nil, nil, fmt.Errorf("合并配置文件[%s]错误: %w", l.file, err)
+		}
+		files = append(files, fs...)
+	}
+
+	// 远程配置
+	for _, r := range rs {
+		m, err := r.settings()
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取远程配置[%s]错误: %w", r.conf.URL, err)
+		}
+		if err := v.MergeConfigMap(m); err != nil {
+			return nil, nil, fmt.Errorf("合并远程配置[%s]错误: %w", r.conf.URL, err)
+		}
+		files = append(files, r.conf.URL)
+	}
+
+	// 环境变量覆盖
+	if err := applyEnvOverlay(scope, v); err != nil {
+		return nil, nil, fmt.Errorf("合并配置[%s]环境变量覆盖错误: %w", scope, err)
+	}
+
+	// 密钥引用与加密值
+	if err := resolveSecrets(v); err != nil {
+		return nil, nil, fmt.Errorf("解析配置[%s]密钥错误: %w", scope, err)
+	}
+	return v, files, nil
+}
+
+// detectEnv 根据基础配置与环境变量确定应用环境
+func detectEnv(l layer) (string, error) {
+	ls := []layer{}
+	if l.file != "" {
+		ls = append(ls, l)
+	}
+	v, _, err := load(defaultScope, ls, nil)
+	if err != nil {
+		return "", err
+	}
+	return normalizeEnv(v.GetString("app.env")), nil
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// 远程配置源默认值
+const (
+	defaultRemoteInterval = 30 * time.Second
+	defaultRemoteTimeout  = 5 * time.Second
+	defaultRemoteCacheDir = "./runtime/config"
+)
+
+// RemoteConfig 远程配置源 在基础配置config.yaml[app.remote]中声明
+// 远程内容 (yaml或json) 作为目标scope的一个配置层 合并在本地文件之后、环境变量之前
+type RemoteConfig struct {
+	// URL 配置地址 请求时携带If-None-Match 服务端返回304时视为未变更
+	URL string `mapstructure:"url" json:"url" yaml:"url" validate:"required,url"`
+	// Scope 合并到的scope 默认为config
+	Scope string `mapstructure:"scope" json:"scope" yaml:"scope"`
+	// Format 内容格式 为空时根据Content-Type与URL扩展名推断 默认yaml
+	Format string `mapstructure:"format" json:"format" yaml:"format" validate:"omitempty,oneof=yaml json"`
+	// Interval 轮询间隔 (仅在开启app.watch时轮询) 变更后从下一次轮询起生效
+	Interval time.Duration `mapstructure:"interval" json:"interval" yaml:"interval" validate:"gte=0"`
+	// Timeout 单次请求超时
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout" validate:"gte=0"`
+	// Cache 本地缓存文件 远程不可用时从缓存启动 默认位于./runtime/config目录
+	Cache string `mapstructure:"cache" json:"cache" yaml:"cache"`
+	// Headers 请求头 (如鉴权Token 可使用${env:NAME}引用)
+	Headers map[string]string `mapstructure:"headers" json:"headers" yaml:"headers"`
+}
+
+// remoteSource 远程配置源运行时状态
+type remoteSource struct {
+	conf RemoteConfig
+
+	mu   sync.Mutex
+	etag string
+	typ  string
+	body []byte
+
+	stop chan struct{}
+}
+
+// remoteCache 本地缓存文件内容
+type remoteCache struct {
+	URL    string `json:"url"`
+	ETag   string `json:"etag"`
+	Format string `json:"format"`
+	Body   string `json:"body"`
+}
+
+var (
+	// remotes 当前生效的远程配置源 key为remoteKey
+	remotes  = map[string]*remoteSource{}
+	remoteMu sync.Mutex
+	// polling 是否已开启远程配置轮询 (Watch)
+	polling bool
+)
+
+func remoteKey(conf RemoteConfig) string {
+	return conf.Scope + "|" + conf.URL
+}
+
+// isRemote 判断配置层是否为远程配置源
+func isRemote(file string) bool {
+	return strings.Contains(file, "://")
+}
+
+// prepareRemotes 根据默认scope的本地配置准备远程配置源
+// 已存在的配置源复用其最近一次获取的内容 新增的配置源立即拉取 (失败时回退本地缓存)
+func prepareRemotes(ls []layer) ([]*remoteSource, error) {
+	v, _, err := load(defaultScope, ls, nil)
+	if err != nil {
+		return nil, err
+	}
+	var confs []RemoteConfig
+	if err := v.UnmarshalKey("app.remote", &confs); err != nil {
+		return nil, fmt.Errorf("解析远程配置源[app.remote]错误: %w", err)
+	}
+
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	srcs := make([]*remoteSource, 0, len(confs))
+	for _, conf := range confs {
+		if conf.URL == "" {
+			return nil, fmt.Errorf("远程配置源[app.remote]缺少url")
+		}
+		if conf.Scope == "" {
+			conf.Scope = defaultScope
+		}
+		if conf.Interval <= 0 {
+			conf.Interval = defaultRemoteInterval
+		}
+		if conf.Timeout <= 0 {
+			conf.Timeout = defaultRemoteTimeout
+		}
+		if conf.Cache == "" {
+			sum := sha1.Sum([]byte(remoteKey(conf)))
+			conf.Cache = filepath.Join(defaultRemoteCacheDir, hex.EncodeToString(sum[:8])+".json")
+		}
+
+		if src, ok := remotes[remoteKey(conf)]; ok {
+			src.mu.Lock()
+			src.conf = conf
+			src.mu.Unlock()
+			srcs = append(srcs, src)
+			continue
+		}
+		src := &remoteSource{conf: conf}
+		if err := src.init(); err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, src)
+	}
+	return srcs, nil
+}
+
+// commitRemotes 替换当前生效的远程配置源 停止已移除配置源的轮询
+func commitRemotes(srcs []*remoteSource) {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	next := make(map[string]*remoteSource, len(srcs))
+	for _, src := range srcs {
+		next[remoteKey(src.conf)] = src
+	}
+	for key, src := range remotes {
+		if _, ok := next[key]; !ok && src.stop != nil {
+			close(src.stop)
+		}
+	}
+	remotes = next
+	if polling {
+		for _, src := range remotes {
+			src.startPolling()
+		}
+	}
+}
+
+// pollRemotes 开启全部远程配置源轮询 内容变更时Reload
+func pollRemotes() {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	polling = true
+	for _, src := range remotes {
+		src.startPolling()
+	}
+}
+
+// stopRemotes 停止全部远程配置源轮询
+func stopRemotes() {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	polling = false
+	for _, src := range remotes {
+		if src.stop != nil {
+			close(src.stop)
+			src.stop = nil
+		}
+	}
+}
+
+// init 首次加载 先读取本地缓存再请求远程 远程不可用时使用缓存
+func (s *remoteSource) init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached := s.readCache()
+	if _, err := s.fetch(); err != nil {
+		if !cached {
+			return fmt.Errorf("拉取远程配置[%s]错误且无本地缓存: %w", s.conf.URL, err)
+		}
+		fmt.Fprintf(output, "拉取远程配置[%s]错误, 使用本地缓存[%s]: %s\n", s.conf.URL, s.conf.Cache, err)
+	}
+	return nil
+}
+
+// settings 解析最近一次获取的内容 每次返回新的map 避免合并时被修改
+func (s *remoteSource) settings() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return decodeRemote(s.body, s.typ)
+}
+
+func (s *remoteSource) startPolling() {
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	go s.poll(s.stop)
+}
+
+// poll 按轮询间隔拉取远程配置 每次等待前读取最新的间隔 间隔变更无需重启轮询
+func (s *remoteSource) poll(stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(s.interval()):
+		}
+		s.mu.Lock()
+		url := s.conf.URL
+		changed, err := s.fetch()
+		s.mu.Unlock()
+		if err != nil {
+			fmt.Fprintf(output, "拉取远程配置[%s]错误: %s\n", url, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if err := Reload(); err != nil {
+			fmt.Fprintln(output, "重新加载配置错误:", err)
+		}
+	}
+}
+
+func (s *remoteSource) interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conf.Interval
+}
+
+// fetch 请求远程配置 返回内容是否变更 调用方需持有s.mu
+func (s *remoteSource) fetch() (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, s.conf.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range s.conf.Headers {
+		req.Header.Set(k, v)
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	resp, err := (&http.Client{Timeout: s.conf.Timeout}).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("响应状态码[%d]", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("读取响应错误: %w", err)
+	}
+	typ := s.format(resp.Header.Get("Content-Type"))
+	if _, err := decodeRemote(body, typ); err != nil {
+		return false, err
+	}
+
+	changed := typ != s.typ || !bytes.Equal(body, s.body)
+	s.etag, s.typ, s.body = resp.Header.Get("ETag"), typ, body
+	if err := s.writeCache(); err != nil {
+		fmt.Fprintf(output, "写入远程配置缓存[%s]错误: %s\n", s.conf.Cache, err)
+	}
+	return changed, nil
+}
+
+// format 确定远程内容格式
+func (s *remoteSource) format(contentType string) string {
+	if s.conf.Format != "" {
+		return s.conf.Format
+	}
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mt, "json"):
+			return "json"
+		case strings.HasSuffix(mt, "yaml"):
+			return "yaml"
+		}
+	}
+	if strings.ToLower(path.Ext(strings.SplitN(s.conf.URL, "?", 2)[0])) == ".json" {
+		return "json"
+	}
+	return "yaml"
+}
+
+// readCache 读取本地缓存 返回是否读取成功 调用方需持有s.mu
+func (s *remoteSource) readCache() bool {
+	b, err := os.ReadFile(s.conf.Cache)
+	if err != nil {
+		return false
+	}
+	c := remoteCache{}
+	if err := json.Unmarshal(b, &c); err != nil || c.URL != s.conf.URL {
+		return false
+	}
+	if _, err := decodeRemote([]byte(c.Body), c.Format); err != nil {
+		return false
+	}
+	s.etag, s.typ, s.body = c.ETag, c.Format, []byte(c.Body)
+	return true
+}
+
+// writeCache 写入本地缓存 调用方需持有s.mu
+func (s *remoteSource) writeCache() error {
+	b, err := json.Marshal(remoteCache{URL: s.conf.URL, ETag: s.etag, Format: s.typ, Body: string(s.body)})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.conf.Cache), 0o700); err != nil {
+		return err
+	}
+	tmp := s.conf.Cache + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.conf.Cache)
+}
+
+// decodeRemote 解析远程配置内容
+func decodeRemote(body []byte, typ string) (map[string]any, error) {
+	rv := viper.NewWithOptions(viper.WithDecoderRegistry(decoders))
+	rv.SetConfigType(typ)
+	if err := rv.ReadConfig(bytes.NewReader(body)); err != nil {
+		return nil, fmt.Errorf("解析远程配置内容错误: %w", err)
+	}
+	return rv.AllSettings(), nil
+}
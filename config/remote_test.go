@@ -0,0 +1,174 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRemote 支持ETag的测试配置服务
+type fakeRemote struct {
+	mu          sync.Mutex
+	etag        string
+	contentType string
+	body        string
+	status      int
+	ifNoneMatch []string
+}
+
+func (f *fakeRemote) set(etag, contentType, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.etag, f.contentType, f.body, f.status = etag, contentType, body, 0
+}
+
+func (f *fakeRemote) fail(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+}
+
+func (f *fakeRemote) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ifNoneMatch = append(f.ifNoneMatch, r.Header.Get("If-None-Match"))
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+	if r.Header.Get("If-None-Match") == f.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", f.etag)
+	w.Header().Set("Content-Type", f.contentType)
+	w.Write([]byte(f.body))
+}
+
+func TestRemoteSourceFetch(t *testing.T) {
+	fake := &fakeRemote{}
+	fake.set(`"v1"`, "application/x-yaml", "feature:\n  toggle: true\n")
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s := &remoteSource{conf: RemoteConfig{URL: srv.URL, Timeout: time.Second, Cache: filepath.Join(t.TempDir(), "remote.json")}}
+	if err := s.init(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		wantChanged bool
+		wantErr     bool
+		ifNoneMatch string
+		want        map[string]any
+	}{
+		{
+			name:        "未变更时返回304",
+			setup:       func() {},
+			ifNoneMatch: `"v1"`,
+			want:        map[string]any{"feature": map[string]any{"toggle": true}},
+		},
+		{
+			name: "内容变更 按Content-Type解析json",
+			setup: func() {
+				fake.set(`"v2"`, "application/json; charset=utf-8", `{"feature": {"toggle": false, "limit": 10}}`)
+			},
+			wantChanged: true,
+			ifNoneMatch: `"v1"`,
+			want:        map[string]any{"feature": map[string]any{"toggle": false, "limit": float64(10)}},
+		},
+		{
+			name:        "服务端错误时保留原内容",
+			setup:       func() { fake.fail(http.StatusInternalServerError) },
+			wantErr:     true,
+			ifNoneMatch: `"v2"`,
+			want:        map[string]any{"feature": map[string]any{"toggle": false, "limit": float64(10)}},
+		},
+		{
+			name:        "内容无法解析时保留原内容",
+			setup:       func() { fake.set(`"v3"`, "application/json", `{"feature": `) },
+			wantErr:     true,
+			ifNoneMatch: `"v2"`,
+			want:        map[string]any{"feature": map[string]any{"toggle": false, "limit": float64(10)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			s.mu.Lock()
+			changed, err := s.fetch()
+			s.mu.Unlock()
+			if (err != nil) != tt.wantErr || changed != tt.wantChanged {
+				t.Fatalf("fetch() = %v, %v, want changed %v, error %v", changed, err, tt.wantChanged, tt.wantErr)
+			}
+			fake.mu.Lock()
+			got := fake.ifNoneMatch[len(fake.ifNoneMatch)-1]
+			fake.mu.Unlock()
+			if got != tt.ifNoneMatch {
+				t.Fatalf("If-None-Match = %s, want %s", got, tt.ifNoneMatch)
+			}
+			settings, err := s.settings()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(settings, tt.want) {
+				t.Fatalf("settings() = %#v, want %#v", settings, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteCacheFallback(t *testing.T) {
+	defer resetForTest()()
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	fake := &fakeRemote{}
+	fake.set(`"v1"`, "application/x-yaml", "feature:\n  toggle: remote\n")
+	srv := httptest.NewServer(fake)
+	url := srv.URL + "/config.yaml"
+	writeFiles(t, dir, map[string]string{
+		"conf/config.yaml": "app:\n  name: test\n  remote:\n    - url: " + url + "\n      timeout: 1s\nfeature:\n  toggle: local\n",
+	})
+
+	// 远程可用 内容覆盖本地配置并写入默认缓存目录
+	if err := Boot("conf"); err != nil {
+		t.Fatal(err)
+	}
+	if got := Pick().GetString("feature.toggle"); got != "remote" {
+		t.Fatalf("feature.toggle = %q, want remote", got)
+	}
+	caches, _ := filepath.Glob(filepath.Join(defaultRemoteCacheDir, "*.json"))
+	if len(caches) != 1 {
+		t.Fatalf("cache files = %v, want one file in %s", caches, defaultRemoteCacheDir)
+	}
+
+	// 远程不可用 从缓存启动
+	srv.Close()
+	restore := resetForTest()
+	err := Boot("conf")
+	toggle := Pick().GetString("feature.toggle")
+	restore()
+	if err != nil {
+		t.Fatalf("Boot() with cache error = %v", err)
+	}
+	if toggle != "remote" {
+		t.Fatalf("feature.toggle = %q, want remote from cache", toggle)
+	}
+
+	// 远程不可用且无缓存
+	writeFiles(t, dir, map[string]string{caches[0]: "{}"})
+	restore = resetForTest()
+	err = Boot("conf")
+	restore()
+	if err == nil || !strings.Contains(err.Error(), "无本地缓存") {
+		t.Fatalf("Boot() without cache error = %v, want 无本地缓存", err)
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+// Reset 保存当前配置状态 (已加载的scope、变更订阅、文件监听与远程配置源) 并重置为空白状态
+// 返回的restore会停止空白状态下开启的监听与轮询 并恢复原状态
+// 配置实例挂载在do默认容器中 需配合替换do.DefaultInjector使用 (见kernel.Reset)
+// 直接替换全局状态 同一时刻仅允许一个重置状态处于活动中
+func Reset() (restore func()) {
+	watchMu.Lock()
+	prevWatcher := watcher
+	watcher = nil
+	watchMu.Unlock()
+
+	mu.Lock()
+	prevPath, prevLayers := bootPath, layers
+	bootPath, layers = "", map[string][]string{}
+	mu.Unlock()
+
+	subsMu.Lock()
+	prevSubs := subs
+	subs = nil
+	subsMu.Unlock()
+
+	remoteMu.Lock()
+	prevRemotes, prevPolling := remotes, polling
+	remotes, polling = map[string]*remoteSource{}, false
+	remoteMu.Unlock()
+
+	return func() {
+		stopWatch()
+
+		watchMu.Lock()
+		watcher = prevWatcher
+		watchMu.Unlock()
+
+		mu.Lock()
+		bootPath, layers = prevPath, prevLayers
+		mu.Unlock()
+
+		subsMu.Lock()
+		subs = prevSubs
+		subsMu.Unlock()
+
+		remoteMu.Lock()
+		remotes, polling = prevRemotes, prevPolling
+		remoteMu.Unlock()
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// MasterKeyEnv 存放配置加密主密钥的环境变量 值为base64编码的16/24/32字节AES密钥
+const MasterKeyEnv = "MYGO_MASTER_KEY"
+
+const (
+	encPrefix = "ENC("
+	encSuffix = ")"
+)
+
+// refPattern 匹配 ${kind:value} 形式的密钥引用
+var refPattern = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// resolveSecrets 解析配置中的密钥引用与加密值 将结果合并回配置实例
+// 支持:
+//   - ${env:NAME}      读取环境变量NAME
+//   - ${file:/path}    读取文件内容 (去除末尾换行)
+//   - ENC(base64...)   使用MYGO_MASTER_KEY进行AES-GCM解密
+func resolveSecrets(v *viper.Viper) error {
+	resolved, changed, err := resolveValue("", v.AllSettings())
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	return v.MergeConfigMap(resolved.(map[string]any))
+}
+
+// resolveValue 递归解析配置值 返回解析后的值以及是否发生变化
+func resolveValue(key string, val any) (any, bool, error) {
+	switch x := val.(type) {
+	case string:
+		s, err := resolveString(key, x)
+		if err != nil {
+			return nil, false, err
+		}
+		return s, s != x, nil
+	case map[string]any:
+		out := map[string]any{}
+		for k, item := range x {
+			r, changed, err := resolveValue(joinKey(key, k), item)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				out[k] = r
+			}
+		}
+		return out, len(out) != 0, nil
+	case []any:
+		out := make([]any, len(x))
+		changed := false
+		for i, item := range x {
+			r, c, err := resolveValue(fmt.Sprintf("%s[%d]", key, i), item)
+			if err != nil {
+				return nil, false, err
+			}
+			if !c {
+				r = item
+			} else if m, ok := item.(map[string]any); ok {
+				// map仅返回了变化的键 列表元素需保留完整内容
+				full := copyMap(m)
+				deepMerge(full, r.(map[string]any))
+				r = full
+			}
+			out[i] = r
+			changed = changed || c
+		}
+		return out, changed, nil
+	}
+	return val, false, nil
+}
+
+// resolveString 解析单个字符串值
+func resolveString(key, s string) (string, error) {
+	// 加密值
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, encPrefix) && strings.HasSuffix(trimmed, encSuffix) {
+		plain, err := Decrypt(trimmed)
+		if err != nil {
+			return "", fmt.Errorf("解密配置[%s]错误: %w", key, err)
+		}
+		return plain, nil
+	}
+
+	// 密钥引用
+	var rerr error
+	out := refPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := refPattern.FindStringSubmatch(m)
+		kind, ref := sub[1], strings.TrimSpace(sub[2])
+		switch kind {
+		case "env":
+			val, ok := os.LookupEnv(ref)
+			if !ok && rerr == nil {
+				rerr = fmt.Errorf("解析配置[%s]错误: 环境变量[%s]不存在", key, ref)
+			}
+			return val
+		case "file":
+			b, err := os.ReadFile(ref)
+			if err != nil && rerr == nil {
+				rerr = fmt.Errorf("解析配置[%s]错误: 读取文件[%s]错误: %w", key, ref, err)
+			}
+			return strings.TrimRight(string(b), "\r\n")
+		}
+		return m
+	})
+	if rerr != nil {
+		return "", rerr
+	}
+	return out, nil
+}
+
+// copyMap 深拷贝map (仅拷贝嵌套map)
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if sub, ok := v.(map[string]any); ok {
+			v = copyMap(sub)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// masterKey 读取配置加密主密钥
+func masterKey() ([]byte, error) {
+	raw := os.Getenv(MasterKeyEnv)
+	if raw == "" {
+		return nil, fmt.Errorf("未设置配置加密主密钥环境变量[%s]", MasterKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("配置加密主密钥[%s]不是合法的base64: %w", MasterKeyEnv, err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	}
+	return nil, fmt.Errorf("配置加密主密钥[%s]长度须为16/24/32字节, 实际为%d字节", MasterKeyEnv, len(key))
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := masterKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// GenerateMasterKey 生成一个base64编码的32字节配置加密主密钥
+func GenerateMasterKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt 使用MYGO_MASTER_KEY加密明文 返回可直接写入配置文件的ENC(...)值
+func Encrypt(plain string) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed) + encSuffix, nil
+}
+
+// Decrypt 使用MYGO_MASTER_KEY解密ENC(...)值
+func Decrypt(enc string) (string, error) {
+	enc = strings.TrimSpace(enc)
+	if !strings.HasPrefix(enc, encPrefix) || !strings.HasSuffix(enc, encSuffix) {
+		return "", errors.New("加密值格式应为ENC(...)")
+	}
+	data, err := base64.StdEncoding.DecodeString(enc[len(encPrefix) : len(enc)-len(encSuffix)])
+	if err != nil {
+		return "", fmt.Errorf("加密值不是合法的base64: %w", err)
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("加密值长度不足")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("解密失败: %w", err)
+	}
+	return string(plain), nil
+}
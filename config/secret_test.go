@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	key, err := GenerateMasterKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(MasterKeyEnv, key)
+	t.Setenv("TEST_JWT_SECRET", "jwt-secret")
+	enc, err := Encrypt("db-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     map[string]any
+		wantErr  string
+	}{
+		{
+			name: "环境变量、文件引用与加密值",
+			settings: map[string]any{
+				"jwt":  map[string]any{"secret": "${env:TEST_JWT_SECRET}", "ttl": 3600},
+				"db":   map[string]any{"password": enc, "dsn": "root:${env:TEST_JWT_SECRET}@tcp(127.0.0.1)/app"},
+				"auth": map[string]any{"token": "${file:" + secretFile + "}"},
+			},
+			want: map[string]any{
+				"jwt":  map[string]any{"secret": "jwt-secret", "ttl": 3600},
+				"db":   map[string]any{"password": "db-pass", "dsn": "root:jwt-secret@tcp(127.0.0.1)/app"},
+				"auth": map[string]any{"token": "file-token"},
+			},
+		},
+		{
+			name: "列表元素保留未变化的键",
+			settings: map[string]any{
+				"notify": []any{
+					map[string]any{"name": "ops", "secret": "${env:TEST_JWT_SECRET}"},
+					"plain",
+				},
+			},
+			want: map[string]any{
+				"notify": []any{
+					map[string]any{"name": "ops", "secret": "jwt-secret"},
+					"plain",
+				},
+			},
+		},
+		{
+			name:     "环境变量不存在",
+			settings: map[string]any{"jwt": map[string]any{"secret": "${env:TEST_NOT_EXIST}"}},
+			wantErr:  "解析配置[jwt.secret]错误: 环境变量[TEST_NOT_EXIST]不存在",
+		},
+		{
+			name:     "文件不存在",
+			settings: map[string]any{"token": "${file:/not/exist}"},
+			wantErr:  "解析配置[token]错误: 读取文件[/not/exist]错误",
+		},
+		{
+			name:     "加密值损坏",
+			settings: map[string]any{"db": map[string]any{"password": "ENC(bm90LXZhbGlk)"}},
+			wantErr:  "解密配置[db.password]错误",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			if err := v.MergeConfigMap(tt.settings); err != nil {
+				t.Fatal(err)
+			}
+			err := resolveSecrets(v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveSecrets() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.AllSettings(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("resolveSecrets() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecryptWithoutMasterKey(t *testing.T) {
+	t.Setenv(MasterKeyEnv, "")
+	if _, err := Decrypt("ENC(AAAA)"); err == nil || !strings.Contains(err.Error(), MasterKeyEnv) {
+		t.Fatalf("Decrypt() error = %v, want missing master key", err)
+	}
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/samber/do"
+	"github.com/spf13/viper"
+)
+
+// Listener 配置变更回调 v为变更后的scope配置实例
+type Listener func(v *viper.Viper) error
+
+type subscription struct {
+	scope string
+	key   string
+	fn    Listener
+}
+
+// watchDebounce 文件变更事件合并等待时长 (编辑器保存时往往连续触发多个事件)
+const watchDebounce = 200 * time.Millisecond
+
+var (
+	subs     []subscription
+	subsMu   sync.Mutex
+	reloadMu sync.Mutex
+
+	// watcher 配置文件监听器 未开启监听时为nil
+	watcher *fsnotify.Watcher
+	watchMu sync.Mutex
+)
+
+// OnChange 订阅指定scope下key的配置变更 scope为空时为默认scope key为空时订阅整个scope
+// 回调在配置实例替换完成后同步执行 此时Pick获取到的即为新配置
+func OnChange(scope, key string, fn Listener) {
+	if scope == "" {
+		scope = defaultScope
+	}
+	subsMu.Lock()
+	defer subsMu.Unlock()
+	subs = append(subs, subscription{scope: scope, key: key, fn: fn})
+}
+
+// Reload 重新读取Boot目录下全部配置 (远程配置源使用最近一次拉取的内容) 并替换实例 然后通知订阅者
+// 任一scope读取失败时整体放弃 保留原配置实例
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	mu.RLock()
+	path := bootPath
+	mu.RUnlock()
+	if path == "" {
+		return errors.New("配置尚未Boot, 无法重新加载")
+	}
+
+	scopeLayers, err := scan(path)
+	if err != nil {
+		return err
+	}
+	instances, srcs, err := loadAll(scopeLayers)
+	if err != nil {
+		return err
+	}
+
+	// 替换配置实例
+	olds := make(map[string]*viper.Viper, len(instances))
+	news := make(map[string]*viper.Viper, len(instances))
+	mu.Lock()
+	for scope, l := range instances {
+		if old, err := do.InvokeNamed[*viper.Viper](nil, iocPrefix+scope); err == nil {
+			olds[scope] = old
+		}
+		news[scope] = l.v
+		layers[scope] = l.files
+		do.OverrideNamedValue(nil, iocPrefix+scope, l.v)
+	}
+	commitRemotes(srcs)
+	mu.Unlock()
+
+	notify(olds, news)
+	return nil
+}
+
+// notify 通知配置发生变更的订阅者
+func notify(olds, news map[string]*viper.Viper) {
+	subsMu.Lock()
+	list := make([]subscription, len(subs))
+	copy(list, subs)
+	subsMu.Unlock()
+
+	for _, sub := range list {
+		v, ok := news[sub.scope]
+		if !ok {
+			continue
+		}
+		if old, ok := olds[sub.scope]; ok && reflect.DeepEqual(settingOf(old, sub.key), settingOf(v, sub.key)) {
+			continue
+		}
+		if err := callListener(sub.fn, v); err != nil {
+			fmt.Fprintf(output, "处理配置[%s][%s]变更错误: %s\n", sub.scope, sub.key, err)
+		}
+	}
+}
+
+func callListener(fn Listener, v *viper.Viper) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(v)
+}
+
+func settingOf(v *viper.Viper, key string) any {
+	if key == "" {
+		return v.AllSettings()
+	}
+	return v.Get(key)
+}
+
+// Watch 监听Boot目录 (含环境覆盖目录及include引入文件所在目录) 下的配置文件变更 变更时自动Reload
+// 同时开启远程配置源轮询 远程内容变更时同样Reload
+// 已开启监听时重复调用不做处理
+func Watch() error {
+	watchMu.Lock()
+	defer watchMu.Unlock()
+	if watcher != nil {
+		return nil
+	}
+
+	mu.RLock()
+	path := bootPath
+	mu.RUnlock()
+	if path == "" {
+		return errors.New("配置尚未Boot, 无法监听变更")
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("创建配置文件监听器错误: %w", err)
+	}
+	dirs := []string{path}
+	for _, env := range []string{AppEnvDev, AppEnvTest, AppEnvProd} {
+		if fi, err := os.Stat(filepath.Join(path, env)); err == nil && fi.IsDir() {
+			dirs = append(dirs, filepath.Join(path, env))
+		}
+	}
+	// include/$ref引入的文件可能位于其他目录
+	mu.RLock()
+	for _, files := range layers {
+		for _, f := range files {
+			if isRemote(f) {
+				continue
+			}
+			if dir := filepath.Dir(f); !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	mu.RUnlock()
+	for _, dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			w.Close()
+			return fmt.Errorf("监听配置目录[%s]错误: %w", dir, err)
+		}
+	}
+	watcher = w
+	go watchLoop(w)
+	pollRemotes()
+	return nil
+}
+
+// stopWatch 停止监听配置文件变更与远程配置源轮询
+func stopWatch() {
+	watchMu.Lock()
+	defer watchMu.Unlock()
+	if watcher != nil {
+		watcher.Close()
+		watcher = nil
+	}
+	stopRemotes()
+}
+
+func watchLoop(w *fsnotify.Watcher) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if _, ok := extMap[filepath.Ext(event.Name)]; !ok {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(watchDebounce, func() {
+				if err := Reload(); err != nil {
+					fmt.Fprintln(output, "重新加载配置错误:", err)
+				}
+			})
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			fmt.Fprintln(output, "监听配置文件错误:", err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/samber/do"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 )
 
@@ -16,8 +17,13 @@ const (
 )
 
 // Boot 预加载默认实例，同时加载指定实例列表
-func Boot(scopes ...string) func() error {
-	return func() error {
+// 引导步骤名为cube 依赖resty
+func Boot(scopes ...string) kernel.Step {
+	kernel.RegisterConfig(defaultScope, DefaultConfig)
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope, func() error {
 		if err := provide(defaultScope); err != nil {
 			return fmt.Errorf("加载资源[%s]错误: %w", defaultScope, err)
 		}
@@ -27,7 +33,7 @@ func Boot(scopes ...string) func() error {
 			}
 		}
 		return nil
-	}
+	}, "resty")
 }
 
 // Exist 判断 scope 实例是否挂载 (被 Boot 过) 且类型正确
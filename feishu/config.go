@@ -30,7 +30,7 @@ var DefaultConfig = Config{
 
 type Config struct {
 	Enable        bool   `mapstructure:"enable"`
-	NoticeWebhook string `mapstructure:"notice_webhook"`
+	NoticeWebhook string `mapstructure:"notice_webhook" validate:"required_if=Enable true"`
 	NoticeSecret  string `mapstructure:"notice_secret"`
 
 	Timeout time.Duration `mapstructure:"timeout"`
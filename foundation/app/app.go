@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/crontab"
+	"github.com/zjutjh/mygo/foundation/httpserver"
+	"github.com/zjutjh/mygo/foundation/kernel"
+)
+
+// defaultConfigScope 默认配置scope 与config包保持一致
+const defaultConfigScope = "config"
+
+// active 是否有App处于活动状态 (已Boot且未Stop)
+var active atomic.Bool
+
+// App 可嵌入的应用实例 任一环节失败时返回错误而非退出进程 可在测试中使用
+// 资源仍注册在进程级全局状态中 (provider的Pick不区分App): Boot时重置全局状态 (见kernel.Reset) Stop后恢复
+// 因此同一时刻仅允许一个App处于活动状态 多个App需依次Boot与Stop 不支持并行测试 (如t.Parallel)
+type App struct {
+	opts options
+
+	mu      sync.Mutex
+	booted  bool
+	restore func()
+	server  *httpserver.Server
+	cron    *crontab.Engine
+}
+
+// New 创建应用实例
+func New(opts ...Option) *App {
+	a := &App{opts: options{confPath: "conf/"}}
+	for _, opt := range opts {
+		opt(&a.opts)
+	}
+	return a
+}
+
+// Boot 加载配置、引导资源并初始化HTTP Server与定时任务引擎 (不启动)
+func (a *App) Boot(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.boot(ctx)
+}
+
+func (a *App) boot(ctx context.Context) (err error) {
+	if a.booted {
+		return nil
+	}
+	if !active.CompareAndSwap(false, true) {
+		return errors.New("已有App处于活动状态, 请先Stop (App共享进程级全局状态 不支持并行)")
+	}
+	a.restore = kernel.Reset()
+	defer func() {
+		if err != nil {
+			kernel.Shutdown(ctx)
+			a.release()
+		}
+	}()
+
+	// 加载配置与引导资源
+	if a.opts.confMap != nil {
+		if err := config.BootMap(a.opts.confMap); err != nil {
+			return err
+		}
+		if err := kernel.BootResources(ctx, a.opts.boot); err != nil {
+			return err
+		}
+	} else if err := kernel.Boot(ctx, a.opts.confPath, a.opts.boot); err != nil {
+		return err
+	}
+
+	// 初始化HTTP Server与定时任务引擎
+	if a.opts.routes != nil {
+		if a.server, err = httpserver.NewServer(a.opts.routes); err != nil {
+			return err
+		}
+	}
+	if a.opts.jobs != nil {
+		if a.cron, err = crontab.NewEngine(a.opts.jobs); err != nil {
+			return err
+		}
+	}
+	a.booted = true
+	return nil
+}
+
+// Engine 获取gin引擎 可直接用于httptest 未设置路由或未Boot时为nil
+func (a *App) Engine() *gin.Engine {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.server == nil {
+		return nil
+	}
+	return a.server.Engine()
+}
+
+// Addr 获取HTTP Server实际监听地址 (配置addr为":0"时可用于获取随机端口)
+func (a *App) Addr() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.server == nil {
+		return ""
+	}
+	return a.server.Addr()
+}
+
+// Start 启动应用 未Boot时先Boot 然后开始监听HTTP请求与调度定时任务
+func (a *App) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.boot(ctx); err != nil {
+		return err
+	}
+	if a.server != nil {
+		if err := a.server.Start(); err != nil {
+			return err
+		}
+	}
+	if a.cron != nil {
+		a.cron.Start()
+	}
+	return nil
+}
+
+// Stop 优雅关闭HTTP Server与定时任务引擎 关闭全部资源并恢复原环境
+func (a *App) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.booted {
+		return nil
+	}
+
+	var errs []error
+	if a.server != nil {
+		errs = append(errs, a.server.Shutdown(ctx))
+	}
+	if a.cron != nil {
+		errs = append(errs, a.cron.Stop(ctx))
+	}
+	errs = append(errs, kernel.Shutdown(ctx))
+	a.release()
+	a.booted = false
+	a.server, a.cron = nil, nil
+	return errors.Join(errs...)
+}
+
+// release 恢复原全局状态并释放活动状态
+func (a *App) release() {
+	a.restore()
+	a.restore = nil
+	active.Store(false)
+}
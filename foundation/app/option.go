@@ -0,0 +1,63 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+
+	"github.com/zjutjh/mygo/foundation/kernel"
+)
+
+// Option 应用配置项
+type Option func(*options)
+
+type options struct {
+	confPath string
+	confMap  map[string]map[string]any
+	boot     func() kernel.BootList
+	routes   func(*gin.Engine)
+	jobs     func(*cron.Cron)
+}
+
+// WithConfigPath 从配置目录加载配置 默认为conf/
+func WithConfigPath(path string) Option {
+	return func(o *options) {
+		o.confPath = path
+		o.confMap = nil
+	}
+}
+
+// WithConfig 以内存中的配置内容作为默认scope (config.yaml) 不读取配置目录
+func WithConfig(settings map[string]any) Option {
+	return WithScopeConfig(defaultConfigScope, settings)
+}
+
+// WithScopeConfig 以内存中的配置内容作为指定scope 不读取配置目录
+func WithScopeConfig(scope string, settings map[string]any) Option {
+	return func(o *options) {
+		if o.confMap == nil {
+			o.confMap = map[string]map[string]any{}
+		}
+		o.confMap[scope] = settings
+	}
+}
+
+// WithBoot 设置资源引导器
+func WithBoot(bootRegister func() kernel.BootList) Option {
+	return func(o *options) {
+		o.boot = bootRegister
+	}
+}
+
+// WithRoutes 设置HTTP路由注册 设置后App提供HTTP Server
+func WithRoutes(routeRegister func(*gin.Engine)) Option {
+	return func(o *options) {
+		o.routes = routeRegister
+	}
+}
+
+// WithCron 设置定时任务注册 设置后App提供定时任务引擎
+func WithCron(jobRegister func(*cron.Cron)) Option {
+	return func(o *options) {
+		o.jobs = jobRegister
+	}
+}
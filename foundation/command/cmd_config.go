@@ -0,0 +1,179 @@
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
+)
+
+// configCmd 框架内置配置管理命令 (无需引导资源)
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "配置管理",
+	Long:  "配置管理 (无需引导资源)",
+}
+
+var configEncryptCmd = &cobra.Command{
+	Use:           "encrypt [plaintext]",
+	Short:         "加密配置值",
+	Long:          fmt.Sprintf("使用环境变量[%s]中的主密钥加密配置值, 输出可直接写入配置文件的ENC(...)值; 未指定plaintext时从标准输入读取", config.MasterKeyEnv),
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var plain string
+		if len(args) == 1 {
+			plain = args[0]
+		} else {
+			line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("读取标准输入错误: %w", err)
+			}
+			plain = strings.TrimRight(line, "\r\n")
+		}
+		enc, err := config.Encrypt(plain)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), enc)
+		return nil
+	},
+}
+
+var configKeygenCmd = &cobra.Command{
+	Use:           "keygen",
+	Short:         "生成配置加密主密钥",
+	Long:          fmt.Sprintf("生成一个base64编码的32字节配置加密主密钥, 用于设置环境变量[%s]", config.MasterKeyEnv),
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := config.GenerateMasterKey()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), key)
+		return nil
+	},
+}
+
+var dumpFormat string
+
+var configDumpCmd = &cobra.Command{
+	Use:           "dump [scope]",
+	Short:         "输出生效配置",
+	Long:          "输出合并后实际生效的配置 (默认scope为config), 密码、密钥、webhook等敏感字段会被脱敏",
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.Boot(cfgPath); err != nil {
+			return fmt.Errorf("%w: 加载配置错误: %w", kernel.ErrConfig, err)
+		}
+		scope := ""
+		if len(args) == 1 {
+			scope = args[0]
+		}
+		if scope != "" && !config.Exist(scope) {
+			return fmt.Errorf("配置scope[%s]不存在, 可选: %s", scope, strings.Join(config.Scopes(), ", "))
+		}
+		settings := redact(config.Pick(scope).AllSettings())
+
+		w := cmd.OutOrStdout()
+		switch dumpFormat {
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(settings)
+		case "yaml":
+			enc := yaml.NewEncoder(w)
+			enc.SetIndent(2)
+			defer enc.Close()
+			return enc.Encode(settings)
+		}
+		return fmt.Errorf("不支持的输出格式[%s], 可选: yaml, json", dumpFormat)
+	},
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:           "check",
+	Short:         "校验配置",
+	Long:          "执行与引导时相同的配置校验 (不引导资源), 发现问题时以非零状态退出",
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.Boot(cfgPath); err != nil {
+			return fmt.Errorf("%w: 加载配置错误: %w", kernel.ErrConfig, err)
+		}
+		// 仅注册引导器以收集各资源的配置结构 不执行引导
+		if boot != nil {
+			boot()
+		}
+		if err := kernel.CheckConfig(); err != nil {
+			return fmt.Errorf("%w: %w", kernel.ErrConfig, err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "配置校验通过")
+		return nil
+	},
+}
+
+// redactKeys 需要脱敏的配置键关键字
+var redactKeys = []string{"password", "secret", "webhook", "token", "api_key", "aes_key", "private_key", "authorization", "credential", "dsn"}
+
+const redacted = "******"
+
+// redact 对配置中的敏感字段脱敏 包括列表中的配置
+func redact(settings map[string]any) map[string]any {
+	out := make(map[string]any, len(settings))
+	for k, v := range settings {
+		if isSecretKey(k) && v != nil && v != "" {
+			if _, ok := v.(map[string]any); !ok {
+				out[k] = redacted
+				continue
+			}
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+// redactValue 对嵌套的配置与列表递归脱敏
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return redact(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = redactValue(e)
+		}
+		return out
+	}
+	return v
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range redactKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	configDumpCmd.Flags().StringVar(&dumpFormat, "format", "yaml", "output format: yaml|json")
+	configCmd.AddCommand(configEncryptCmd, configKeygenCmd, configDumpCmd, configCheckCmd)
+	root.AddCommand(configCmd)
+}
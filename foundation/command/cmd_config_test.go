@@ -0,0 +1,50 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	settings := map[string]any{
+		"name":     "app",
+		"password": "p@ss",
+		"empty":    map[string]any{"token": ""},
+		"db": map[string]any{
+			"host":     "127.0.0.1",
+			"password": "db-pass",
+			"dsn":      "user:db-pass@tcp(127.0.0.1:3306)/app",
+		},
+		"gcp": map[string]any{"private_key": "-----BEGIN", "credentials_file": "/etc/gcp.json"},
+		"notify": []any{
+			map[string]any{"name": "ops", "webhook": "https://example.com/hook"},
+			map[string]any{"name": "dev", "auth": []any{map[string]any{"api_key": "k1"}}},
+			"plain",
+		},
+	}
+	want := map[string]any{
+		"name":     "app",
+		"password": redacted,
+		"empty":    map[string]any{"token": ""},
+		"db": map[string]any{
+			"host":     "127.0.0.1",
+			"password": redacted,
+			"dsn":      redacted,
+		},
+		"gcp": map[string]any{"private_key": redacted, "credentials_file": redacted},
+		"notify": []any{
+			map[string]any{"name": "ops", "webhook": redacted},
+			map[string]any{"name": "dev", "auth": []any{map[string]any{"api_key": redacted}}},
+			"plain",
+		},
+	}
+
+	got := redact(settings)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("redact() = %#v, want %#v", got, want)
+	}
+	// 不修改原配置
+	if settings["notify"].([]any)[0].(map[string]any)["webhook"] != "https://example.com/hook" {
+		t.Fatal("redact() modified the input settings")
+	}
+}
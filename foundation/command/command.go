@@ -1,10 +1,15 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"runtime/debug"
 	"runtime/pprof"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -14,26 +19,34 @@ import (
 	"github.com/zjutjh/mygo/nlog"
 )
 
+// ErrTimeout 命令执行超时 命令context以此为原因取消
+var ErrTimeout = errors.New("命令执行超时")
+
 var cfgPath string
+var output string
 
 var boot func() kernel.BootList
 var defaultRun func(cmd *cobra.Command, args []string) error
 var runningCommand *cobra.Command
 
 var once sync.Once
+
+// builtins 组件包通过AddBuiltin注册的内置命令
+var builtins []*cobra.Command
 var root = &cobra.Command{
 	Use: "app",
 	Run: func(cmd *cobra.Command, args []string) {
 		if runningCommand == nil || (runningCommand.Use != "app" && runningCommand.Use != "server") {
 			runningCommand = cmd
 		}
-		kernel.Bootstrap(cfgPath, boot)
-		Run(defaultRun, cmd, args)
+		execute(defaultRun, cmd, args, nil)
 	},
 }
 
 func init() {
+	kernel.RegisterOptionalConfig("command", DefaultConfig)
 	root.PersistentFlags().StringVar(&cfgPath, "config", "conf/", "config path(default is conf/)")
+	root.PersistentFlags().StringVar(&output, "output", OutputText, "result output format: text|json")
 }
 
 // Execute 应用程序执行主入口
@@ -45,64 +58,171 @@ func Execute(b func() kernel.BootList, rc func(*cobra.Command), dr func(cmd *cob
 
 		// 注册业务命令
 		rc(root)
+		mountBuiltins()
 
-		// 执行
-		if err := root.Execute(); err != nil {
-			fmt.Fprintln(os.Stdout, "执行命令错误", err)
-			os.Exit(1)
+		// 执行 命令行解析与参数校验错误同样输出命令结果
+		if cmd, err := root.ExecuteC(); err != nil {
+			resolveOutput(os.Args[1:])
+			if err := setupOutput(cmd); err != nil {
+				output = OutputText
+			}
+			fmt.Fprintln(stdout, "执行命令错误", err)
+			r := newResult(cmd, cmd.Flags().Args())
+			r.fail(err)
+			report(r)
 		}
 	})
 }
 
-// Add 注册一个命令
-func Add(key string, runner func(cmd *cobra.Command, args []string) error) {
+// Add 注册一个命令 可通过Option声明说明、别名、位置参数校验与参数绑定
+func Add(key string, runner func(cmd *cobra.Command, args []string) error, opts ...Option) {
+	root.AddCommand(newCommand(key, runner, opts))
+}
+
+// AddBuiltin 注册组件包提供的内置命令 (如migrate) 一般在组件包init中调用
+// 在业务命令注册后挂载到根命令 与业务命令重名时以业务命令为准
+func AddBuiltin(cmd *cobra.Command) {
+	builtins = append(builtins, cmd)
+}
+
+// mountBuiltins 挂载未被业务命令占用名称的内置命令
+func mountBuiltins() {
+	names := map[string]bool{}
+	for _, c := range root.Commands() {
+		names[c.Name()] = true
+	}
+	for _, c := range builtins {
+		if !names[c.Name()] {
+			root.AddCommand(c)
+			names[c.Name()] = true
+		}
+	}
+}
+
+// New 创建一个命令但不注册 供组件包提供可挂载到任意父命令下的命令 选项同Add
+func New(key string, runner func(cmd *cobra.Command, args []string) error, opts ...Option) *cobra.Command {
+	return newCommand(key, runner, opts)
+}
+
+// newCommand 按声明创建命令 参数绑定失败属于编码错误 直接panic
+func newCommand(key string, runner func(cmd *cobra.Command, args []string) error, opts []Option) *cobra.Command {
+	s := spec{}
+	for _, opt := range opts {
+		opt(&s)
+	}
 	cmd := &cobra.Command{
-		Use:   key,
-		Short: fmt.Sprintf("运行命令[%s]", key),
-		Long:  fmt.Sprintf("运行命令[%s]", key),
+		Use:     key,
+		Short:   fmt.Sprintf("运行命令[%s]", key),
+		Aliases: s.aliases,
+		Example: s.example,
+		Hidden:  s.hidden,
 		Run: func(cmd *cobra.Command, args []string) {
 			if runningCommand == nil || (runningCommand.Use != "app" && runningCommand.Use != "server") {
 				runningCommand = cmd
 			}
-			kernel.Bootstrap(cfgPath, boot)
-			Run(runner, cmd, args)
+			execute(runner, cmd, args, &s)
 		},
 	}
-	root.AddCommand(cmd)
+	if s.usage != "" {
+		cmd.Use = key + " " + s.usage
+	}
+	if s.short != "" {
+		cmd.Short = s.short
+	}
+	cmd.Long = cmd.Short
+	if s.long != "" {
+		cmd.Long = s.long
+	}
+	if len(s.args) != 0 {
+		cmd.Args = cobra.MatchAll(s.args...)
+	}
+	for _, ptr := range s.flags {
+		if err := bindFlags(cmd.Flags(), ptr); err != nil {
+			panic(fmt.Sprintf("命令[%s]参数绑定错误: %s", key, err))
+		}
+	}
+	if len(s.flags) != 0 {
+		cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			for _, ptr := range s.flags {
+				if err := validateFlags(ptr); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return cmd
+}
+
+// execute 引导应用并运行命令 输出结果后按结果退出进程
+func execute(runner func(cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string, s *spec) {
+	if err := setupOutput(cmd); err != nil {
+		fmt.Fprintln(stdout, err)
+		os.Exit(ExitRunner)
+	}
+	r := newResult(cmd, args)
+	if err := kernel.Boot(context.Background(), cfgPath, boot); err != nil {
+		fmt.Fprintln(stdout, err)
+		r.fail(err)
+	} else {
+		run(runner, cmd, args, r, s)
+	}
+	report(r)
 }
 
-// Run 运行命令
+// Run 在应用已引导的前提下运行命令 失败时以对应退出码退出进程
+// 退出码: 命令错误ExitRunner 发生panic ExitPanic 配置错误ExitConfig
 func Run(runner func(cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string) {
+	r := newResult(cmd, args)
+	run(runner, cmd, args, r, nil)
+	report(r)
+}
+
+// run 运行命令并记录结果 返回前关闭全部已引导资源 s为命令声明 未通过Add/Group注册时为nil
+func run(runner func(cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string, r *Result, s *spec) {
 	// 初始化配置和日志实例
 	conf := DefaultConfig
 	err := config.Pick().UnmarshalKey("command", &conf)
 	if err != nil {
-		fmt.Fprintln(os.Stdout, "初始化命令配置错误", err)
-		os.Exit(1)
+		fmt.Fprintln(stdout, "初始化命令配置错误", err)
+		r.fail(fmt.Errorf("%w: 初始化命令配置错误: %w", kernel.ErrConfig, err))
+		return
 	}
+	// 静默命令不打印开始与成功提示
+	banner := conf.Output && !(s != nil && s.quiet)
 	logger := nlog.Pick(conf.Logger)
 
+	// 命令结束后关闭全部已引导资源 (日志最后关闭)
+	defer func() {
+		if err := kernel.Shutdown(context.Background()); err != nil {
+			fmt.Fprintln(stdout, "关闭资源错误:", err)
+		}
+	}()
+
 	// 启动pprof
 	if conf.PprofSwitch {
 		for _, t := range conf.PprofType {
 			switch t {
 			case "cpu":
-				w, err := os.OpenFile(conf.PprofOutput+cmd.Use+".run.cpu", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				w, err := os.OpenFile(conf.PprofOutput+cmd.Name()+".run.cpu", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 				if err != nil {
-					fmt.Fprintln(os.Stdout, "处理命令CPU pprof错误", err)
-					os.Exit(1)
+					fmt.Fprintln(stdout, "处理命令CPU pprof错误", err)
+					r.fail(fmt.Errorf("%w: 处理命令CPU pprof错误: %w", kernel.ErrBoot, err))
+					return
 				}
 				err = pprof.StartCPUProfile(w)
 				if err != nil {
-					fmt.Fprintln(os.Stdout, "启动命令CPU pprof错误", err)
-					os.Exit(1)
+					fmt.Fprintln(stdout, "启动命令CPU pprof错误", err)
+					r.fail(fmt.Errorf("%w: 启动命令CPU pprof错误: %w", kernel.ErrBoot, err))
+					return
 				}
 				defer pprof.StopCPUProfile()
 			default:
-				w, err := os.OpenFile(conf.PprofOutput+cmd.Use+".run."+t, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				w, err := os.OpenFile(conf.PprofOutput+cmd.Name()+".run."+t, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 				if err != nil {
-					fmt.Fprintln(os.Stdout, fmt.Sprintf("处理命令%s pprof错误", t), err)
-					os.Exit(1)
+					fmt.Fprintln(stdout, fmt.Sprintf("处理命令%s pprof错误", t), err)
+					r.fail(fmt.Errorf("%w: 处理命令%s pprof错误: %w", kernel.ErrBoot, t, err))
+					return
 				}
 				defer w.Close()
 				defer pprof.Lookup(t).WriteTo(w, 0)
@@ -113,37 +233,81 @@ func Run(runner func(cmd *cobra.Command, args []string) error, cmd *cobra.Comman
 	// 标记开始时间
 	start := time.Now()
 
-	// 处理panic
+	// 处理panic 记录到结果中 不再向上抛出
 	defer func() {
 		if pnc := recover(); pnc != nil {
+			r.Panic = fmt.Sprint(pnc)
+			r.Stack = string(debug.Stack())
+			r.ExitCode = ExitPanic
 			if conf.Output {
-				fmt.Fprintf(os.Stdout, "命令[%s]发生panic, 耗时[%s]\n", cmd.Use, time.Since(start).String())
+				fmt.Fprintf(stdout, "命令[%s]发生panic, 耗时[%s], panic: %s\n%s", cmd.Name(), time.Since(start).String(), r.Panic, r.Stack)
 			}
-			logger.Errorf("命令[%s]发生panic, 耗时[%s]", cmd.Use, time.Since(start).String())
-			panic(pnc)
+			logger.WithField("panic", r.Panic).WithField("stack", r.Stack).Errorf("命令[%s]发生panic, 耗时[%s]", cmd.Name(), time.Since(start).String())
 		}
 	}()
 
+	// 命令context 收到SIGINT/SIGTERM、超时或单实例锁续期失败时取消 runner通过cmd.Context()获取
+	timeout := conf.Timeout
+	if s != nil && s.timeout != nil {
+		timeout = *s.timeout
+	}
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// 首个信号取消context后恢复默认信号处理 未响应取消的runner可再次发送信号强制退出
+	context.AfterFunc(ctx, stop)
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w[%s]", ErrTimeout, timeout))
+		defer cancelTimeout()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	cmd.SetContext(ctx)
+
+	// 单实例运行
+	if s != nil && s.singleInstance {
+		release, err := acquireLock(ctx, cancel, cmd, conf.Lock, logger)
+		if err != nil {
+			if conf.Output {
+				fmt.Fprintf(stdout, "命令[%s]未执行: %s\n", cmd.Name(), err)
+			}
+			logger.WithError(err).Warnf("命令[%s]未执行", cmd.Name())
+			r.fail(err)
+			return
+		}
+		defer release()
+	}
+
 	// 声明开始执行信息
-	if conf.Output {
-		fmt.Fprintf(os.Stdout, "命令[%s]开始执行\n", cmd.Use)
+	if banner {
+		fmt.Fprintf(stdout, "命令[%s]开始执行\n", cmd.Name())
 	}
-	logger.WithField("args", args).Infof("命令[%s]开始执行", cmd.Use)
+	logger.WithField("args", args).Infof("命令[%s]开始执行", cmd.Name())
 
 	// 执行命名逻辑
 	err = runner(cmd, args)
+	// 命令context被取消时附加取消原因
+	if cause := context.Cause(ctx); err != nil && cause != nil && !errors.Is(err, cause) {
+		err = fmt.Errorf("%w (命令已取消: %w)", err, cause)
+	}
 
 	// 声明执行结果
 	if err == nil {
-		if conf.Output {
-			fmt.Fprintf(os.Stdout, "命令[%s]执行成功, 耗时[%s]\n", cmd.Use, time.Since(start).String())
+		if banner {
+			fmt.Fprintf(stdout, "命令[%s]执行成功, 耗时[%s]\n", cmd.Name(), time.Since(start).String())
 		}
-		logger.Infof("命令[%s]执行成功, 耗时[%s]", cmd.Use, time.Since(start).String())
+		logger.Infof("命令[%s]执行成功, 耗时[%s]", cmd.Name(), time.Since(start).String())
 	} else {
 		if conf.Output {
-			fmt.Fprintf(os.Stdout, "命令[%s]发生错误, 耗时[%s], 错误: %s\n", cmd.Use, time.Since(start).String(), err.Error())
+			fmt.Fprintf(stdout, "命令[%s]发生错误, 耗时[%s], 错误: %s\n", cmd.Name(), time.Since(start).String(), err.Error())
 		}
-		logger.WithError(err).Errorf("命令[%s]发生错误, 耗时[%s]", cmd.Use, time.Since(start).String())
+		logger.WithError(err).Errorf("命令[%s]发生错误, 耗时[%s]", cmd.Name(), time.Since(start).String())
+		r.Error = err.Error()
+		r.ExitCode = ExitRunner
 	}
 }
 
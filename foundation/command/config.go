@@ -1,17 +1,34 @@
 package command
 
+import "time"
+
 var DefaultConfig = Config{
 	Logger:      "",
 	Output:      true,
+	Timeout:     0,
 	PprofSwitch: false,
 	PprofOutput: "./",
 	PprofType:   []string{"cpu", "heap"},
+
+	Lock: LockConfig{
+		Lock:   "",
+		Expiry: 30 * time.Second,
+	},
 }
 
 type Config struct {
-	Logger      string   `mapstructure:"logger"`
-	Output      bool     `mapstructure:"output"`
-	PprofSwitch bool     `mapstructure:"pprof_switch"`
-	PprofOutput string   `mapstructure:"pprof_output"`
-	PprofType   []string `mapstructure:"pprof_type"`
+	Logger      string        `mapstructure:"logger"`
+	Output      bool          `mapstructure:"output"`
+	Timeout     time.Duration `mapstructure:"timeout" validate:"gte=0"` // Timeout 命令执行超时时间 超时后取消命令context 0表示不限制
+	PprofSwitch bool          `mapstructure:"pprof_switch"`
+	PprofOutput string        `mapstructure:"pprof_output"`
+	PprofType   []string      `mapstructure:"pprof_type"`
+
+	Lock LockConfig `mapstructure:"lock"`
+}
+
+// LockConfig 单实例运行锁配置 仅对声明了WithSingleInstance的命令生效
+type LockConfig struct {
+	Lock   string        `mapstructure:"lock"`                   // Lock 使用的lock实例scope 为空时使用默认实例
+	Expiry time.Duration `mapstructure:"expiry" validate:"gt=0"` // Expiry 锁过期时间 执行期间每隔Expiry/3自动续期
 }
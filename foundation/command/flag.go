@@ -0,0 +1,114 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/pflag"
+)
+
+// validate 以命令参数名命名字段的校验器
+var validate = newValidator()
+
+// bindFlags 将结构体字段绑定为命令参数 结构体当前的字段值即为参数默认值
+//
+//	flag     参数名 默认为字段名的kebab-case形式 "-"表示不绑定
+//	short    单字母简写
+//	usage    参数说明
+//	validate 参数校验约束 (go-playground/validator) 解析参数后执行
+//
+// 支持的字段类型: string bool int int64 uint uint64 float64 time.Duration []string []int
+func bindFlags(fs *pflag.FlagSet, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("参数绑定对象必须为结构体指针, 实际为%T", ptr)
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := flagName(f)
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if fs.Lookup(name) != nil {
+			return fmt.Errorf("参数[--%s]重复绑定", name)
+		}
+		short, usage := f.Tag.Get("short"), f.Tag.Get("usage")
+		p := v.Field(i).Addr().Interface()
+		switch p := p.(type) {
+		case *string:
+			fs.StringVarP(p, name, short, *p, usage)
+		case *bool:
+			fs.BoolVarP(p, name, short, *p, usage)
+		case *int:
+			fs.IntVarP(p, name, short, *p, usage)
+		case *int64:
+			fs.Int64VarP(p, name, short, *p, usage)
+		case *time.Duration:
+			fs.DurationVarP(p, name, short, *p, usage)
+		case *uint:
+			fs.UintVarP(p, name, short, *p, usage)
+		case *uint64:
+			fs.Uint64VarP(p, name, short, *p, usage)
+		case *float64:
+			fs.Float64VarP(p, name, short, *p, usage)
+		case *[]string:
+			fs.StringSliceVarP(p, name, short, *p, usage)
+		case *[]int:
+			fs.IntSliceVarP(p, name, short, *p, usage)
+		default:
+			return fmt.Errorf("参数[--%s]类型[%s]不支持绑定", name, f.Type)
+		}
+	}
+	return nil
+}
+
+// validateFlags 校验已绑定参数的结构体 汇总返回全部不满足约束的参数
+func validateFlags(ptr any) error {
+	err := validate.Struct(ptr)
+	var ves validator.ValidationErrors
+	if !errors.As(err, &ves) {
+		return err
+	}
+	problems := make([]string, 0, len(ves))
+	for _, fe := range ves {
+		c := fe.Tag()
+		if fe.Param() != "" {
+			c += "=" + fe.Param()
+		}
+		problems = append(problems, fmt.Sprintf("参数[--%s]值[%v]不满足约束[%s]", fe.Field(), fe.Value(), c))
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(flagName)
+	return v
+}
+
+// flagName 字段对应的参数名
+func flagName(f reflect.StructField) string {
+	if name := f.Tag.Get("flag"); name != "" {
+		return name
+	}
+	var b strings.Builder
+	rs := []rune(f.Name)
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			// 连续大写视为缩写 如UserIDs -> user-ids
+			if i > 0 && (unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
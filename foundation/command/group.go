@@ -0,0 +1,62 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Group 命令分组 分组本身不可运行 仅用于组织子命令 如app db migrate
+type Group struct {
+	cmd *cobra.Command
+}
+
+// NewGroup 在根命令下注册命令分组 仅WithShort/WithLong/WithExample/WithAliases/WithHidden生效
+func NewGroup(key string, opts ...Option) *Group {
+	g := newGroup(key, opts)
+	root.AddCommand(g.cmd)
+	return g
+}
+
+// Add 在分组下注册命令
+func (g *Group) Add(key string, runner func(cmd *cobra.Command, args []string) error, opts ...Option) {
+	g.cmd.AddCommand(newCommand(key, runner, opts))
+}
+
+// Group 在分组下注册子分组
+func (g *Group) Group(key string, opts ...Option) *Group {
+	sub := newGroup(key, opts)
+	g.cmd.AddCommand(sub.cmd)
+	return sub
+}
+
+// Command 获取分组对应的cobra命令
+func (g *Group) Command() *cobra.Command {
+	return g.cmd
+}
+
+func newGroup(key string, opts []Option) *Group {
+	s := spec{}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	cmd := &cobra.Command{
+		Use:     key,
+		Short:   fmt.Sprintf("命令分组[%s]", key),
+		Aliases: s.aliases,
+		Example: s.example,
+		Hidden:  s.hidden,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	if s.short != "" {
+		cmd.Short = s.short
+	}
+	cmd.Long = cmd.Short
+	if s.long != "" {
+		cmd.Long = s.long
+	}
+	return &Group{cmd: cmd}
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redsync/redsync/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/lock"
+)
+
+var (
+	// ErrLocked 单实例锁已被其他实例持有
+	ErrLocked = errors.New("命令正在其他实例上运行")
+	// ErrLockLost 执行期间单实例锁续期失败 命令context以此为原因取消
+	ErrLockLost = errors.New("命令单实例锁续期失败")
+)
+
+// lockKey 命令单实例锁的键 形如mygo:command:{app}:app:db:repair
+func lockKey(cmd *cobra.Command) string {
+	return fmt.Sprintf("mygo:command:%s:%s", config.AppName(), strings.ReplaceAll(cmd.CommandPath(), " ", ":"))
+}
+
+// acquireLock 获取命令单实例锁 成功后每隔Expiry/3自动续期 续期失败时以ErrLockLost取消ctx
+// 返回的release用于停止续期并释放锁
+func acquireLock(ctx context.Context, cancel context.CancelCauseFunc, cmd *cobra.Command, conf LockConfig, logger *logrus.Logger) (release func(), err error) {
+	scope := conf.Lock
+	if scope == "" {
+		scope = "lock" // lock默认实例scope
+	}
+	if !lock.Exist(scope) {
+		return nil, fmt.Errorf("命令[%s]声明了单实例运行, 但lock实例[%s]未引导", cmd.Name(), scope)
+	}
+	key := lockKey(cmd)
+	m := lock.Pick(scope).NewMutex(key, redsync.WithExpiry(conf.Expiry), redsync.WithTries(1))
+	if err := m.TryLockContext(ctx); err != nil {
+		var taken *redsync.ErrTaken
+		if errors.Is(err, redsync.ErrFailed) || errors.As(err, &taken) {
+			return nil, fmt.Errorf("%w: 锁[%s]已被持有", ErrLocked, key)
+		}
+		return nil, fmt.Errorf("获取命令单实例锁[%s]错误: %w", key, err)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(conf.Expiry / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if ok, err := m.ExtendContext(context.Background()); !ok || err != nil {
+					logger.WithError(err).Errorf("命令[%s]单实例锁[%s]续期失败, 取消命令执行", cmd.Name(), key)
+					cancel(ErrLockLost)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+		if _, err := m.UnlockContext(context.Background()); err != nil {
+			logger.WithError(err).Warnf("命令[%s]释放单实例锁[%s]错误", cmd.Name(), key)
+		}
+	}, nil
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Option 命令声明项
+type Option func(*spec)
+
+type spec struct {
+	usage   string
+	short   string
+	long    string
+	example string
+	aliases []string
+	args    []cobra.PositionalArgs
+	flags   []any
+	hidden  bool
+
+	timeout        *time.Duration
+	singleInstance bool
+	quiet          bool
+}
+
+// WithShort 命令简述 显示在app --help的命令列表中
+func WithShort(short string) Option {
+	return func(s *spec) {
+		s.short = short
+	}
+}
+
+// WithLong 命令详细说明 显示在命令自身的--help中 未设置时使用简述
+func WithLong(long string) Option {
+	return func(s *spec) {
+		s.long = long
+	}
+}
+
+// WithExample 命令使用示例
+func WithExample(example string) Option {
+	return func(s *spec) {
+		s.example = example
+	}
+}
+
+// WithUsage 位置参数说明 如"<date> [shop...]" 追加在命令名后显示
+func WithUsage(usage string) Option {
+	return func(s *spec) {
+		s.usage = usage
+	}
+}
+
+// WithAliases 命令别名
+func WithAliases(aliases ...string) Option {
+	return func(s *spec) {
+		s.aliases = append(s.aliases, aliases...)
+	}
+}
+
+// WithArgs 位置参数校验 如cobra.ExactArgs(1) 多个校验依次执行
+func WithArgs(validators ...cobra.PositionalArgs) Option {
+	return func(s *spec) {
+		s.args = append(s.args, validators...)
+	}
+}
+
+// WithFlags 将命令参数绑定到结构体指针 (见bindFlags) 可多次调用绑定多个结构体
+func WithFlags(ptr any) Option {
+	return func(s *spec) {
+		s.flags = append(s.flags, ptr)
+	}
+}
+
+// WithHidden 在帮助信息中隐藏命令
+func WithHidden() Option {
+	return func(s *spec) {
+		s.hidden = true
+	}
+}
+
+// WithTimeout 命令执行超时时间 覆盖config.yaml[command.timeout] 0表示不限制
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *spec) {
+		s.timeout = &timeout
+	}
+}
+
+// WithSingleInstance 以命令路径为键的分布式锁保证同一时刻只有一个实例在运行
+// 需引导lock资源 锁已被其他实例持有时命令以ExitLocked退出
+func WithSingleInstance() Option {
+	return func(s *spec) {
+		s.singleInstance = true
+	}
+}
+
+// WithQuiet 不在标准输出打印命令开始与成功提示 (错误提示与日志照常) 用于标准输出即为数据的命令
+func WithQuiet() Option {
+	return func(s *spec) {
+		s.quiet = true
+	}
+}
@@ -0,0 +1,118 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/zjutjh/mygo/foundation/kernel"
+)
+
+// 命令退出码
+const (
+	ExitOK     = 0 // ExitOK 执行成功
+	ExitRunner = 1 // ExitRunner 命令执行错误
+	ExitPanic  = 2 // ExitPanic 命令执行发生panic (与Go运行时panic退出码一致)
+	ExitConfig = 3 // ExitConfig 配置加载或校验错误
+	ExitBoot   = 4 // ExitBoot 资源引导错误
+	ExitLocked = 5 // ExitLocked 单实例锁已被其他实例持有 命令未执行
+)
+
+// 结果输出格式
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Result 命令执行结果 --output=json时以单行JSON输出到标准输出
+type Result struct {
+	Command    string    `json:"command"`
+	Args       []string  `json:"args"`
+	Start      time.Time `json:"start"`
+	Duration   string    `json:"duration"`
+	DurationMs int64     `json:"duration_ms"`
+	ExitCode   int       `json:"exit_code"`
+	Error      string    `json:"error,omitempty"`
+	Panic      string    `json:"panic,omitempty"`
+	Stack      string    `json:"stack,omitempty"`
+}
+
+func newResult(cmd *cobra.Command, args []string) *Result {
+	if args == nil {
+		args = []string{}
+	}
+	return &Result{Command: cmd.CommandPath(), Args: args, Start: time.Now()}
+}
+
+// fail 记录错误 退出码由错误所属阶段决定
+func (r *Result) fail(err error) {
+	r.Error = err.Error()
+	r.ExitCode = exitCode(err)
+}
+
+// exitCode 错误对应的退出码
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, kernel.ErrConfig):
+		return ExitConfig
+	case errors.Is(err, kernel.ErrBoot):
+		return ExitBoot
+	case errors.Is(err, ErrLocked):
+		return ExitLocked
+	}
+	return ExitRunner
+}
+
+// stdout 命令提示信息的输出位置 --output=json时为标准错误 保证标准输出仅包含结果
+var stdout io.Writer = os.Stdout
+
+// resultOut 命令结果的输出位置 仅--output=json时设置
+var resultOut io.Writer
+
+// setupOutput 校验--output 在json模式下将命令、框架提示信息与cobra输出指向标准错误
+func setupOutput(cmd *cobra.Command) error {
+	switch output {
+	case OutputText:
+		return nil
+	case OutputJSON:
+		resultOut = os.Stdout
+		stdout = os.Stderr
+		kernel.SetOutput(os.Stderr)
+		cmd.Root().SetOut(os.Stderr)
+		return nil
+	}
+	return fmt.Errorf("不支持的输出格式[%s], 可选: %s, %s", output, OutputText, OutputJSON)
+}
+
+// resolveOutput 命令行解析失败时--output可能未被解析 宽松解析命令行参数确定输出格式
+func resolveOutput(args []string) {
+	fs := pflag.NewFlagSet("output", pflag.ContinueOnError)
+	fs.ParseErrorsAllowlist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&output, "output", output, "")
+	_ = fs.Parse(args)
+}
+
+// report 输出命令结果 失败时以对应退出码退出进程
+func report(r *Result) {
+	d := time.Since(r.Start)
+	r.Duration = d.String()
+	r.DurationMs = d.Milliseconds()
+
+	if resultOut != nil {
+		if err := json.NewEncoder(resultOut).Encode(r); err != nil {
+			fmt.Fprintln(os.Stderr, "输出命令结果错误", err)
+		}
+	}
+	if r.ExitCode != ExitOK {
+		os.Exit(r.ExitCode)
+	}
+}
@@ -0,0 +1,81 @@
+package crontab
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zjutjh/mygo/foundation/reply"
+	"github.com/zjutjh/mygo/kit"
+	"github.com/zjutjh/mygo/nlog"
+)
+
+// JobStatus 任务状态
+type JobStatus struct {
+	Job
+	Enabled  bool       `json:"enabled"`           // Enabled 本进程引擎中是否启用
+	Next     *time.Time `json:"next,omitempty"`    // Next 本进程引擎中的下次执行时间 未在本进程调度时为空
+	Overlap  Overlap    `json:"overlap,omitempty"` // Overlap 本进程引擎中生效的重叠策略
+	Skipped  int64      `json:"skipped"`           // Skipped 本进程因上次执行未结束跳过的调度次数
+	Delayed  int64      `json:"delayed"`           // Delayed 本进程因上次执行未结束延迟的调度次数
+	Replaced int64      `json:"replaced"`          // Replaced 本进程因新一次调度取消的执行次数
+	Last     *Record    `json:"last,omitempty"`    // Last 最近一次执行记录
+	Records  []Record   `json:"records,omitempty"` // Records 最近的执行记录 仅查询单个任务时返回
+}
+
+// AdminHandler 定时任务管理接口 以标准响应返回已注册任务的状态
+// 不带参数时返回全部任务及最近一次执行记录 带name参数时返回该任务最近的执行记录
+// 例: engine.GET("/admin/cron", middleware..., crontab.AdminHandler())
+func AdminHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if name := ctx.Query("name"); name != "" {
+			j, ok := lookup(name)
+			if !ok {
+				reply.Fail(ctx, kit.CodeDataNotFound)
+				return
+			}
+			records, err := History(ctx.Request.Context(), name)
+			if err != nil {
+				nlog.Pick(loadConfig().Logger).WithError(err).Error("查询定时任务执行历史错误")
+				reply.Fail(ctx, kit.CodeRedisError)
+				return
+			}
+			status := jobStatus(j, records)
+			status.Records = records
+			reply.Success(ctx, status)
+			return
+		}
+
+		list := []JobStatus{}
+		for _, j := range Jobs() {
+			records, err := History(ctx.Request.Context(), j.Name)
+			if err != nil {
+				nlog.Pick(loadConfig().Logger).WithError(err).Error("查询定时任务执行历史错误")
+				reply.Fail(ctx, kit.CodeRedisError)
+				return
+			}
+			list = append(list, jobStatus(j, records))
+		}
+		reply.Success(ctx, list)
+	}
+}
+
+func jobStatus(j Job, records []Record) JobStatus {
+	s := JobStatus{Job: j}
+	if st := j.state; st != nil {
+		p, _ := st.current()
+		s.Spec = p.cronSpec()
+		s.Enabled = p.enabled
+		if next := st.next(); !next.IsZero() {
+			s.Next = &next
+		}
+		s.Overlap = p.overlap
+		s.Skipped = st.skipped.Load()
+		s.Delayed = st.delayed.Load()
+		s.Replaced = st.replaced.Load()
+	}
+	if len(records) > 0 {
+		s.Last = &records[0]
+	}
+	return s
+}
@@ -3,8 +3,22 @@ package crontab
 import "time"
 
 var DefaultConfig = Config{
+	Logger:              "",
+	Timeout:             0,
+	Overlap:             string(OverlapAllow),
 	ShutdownWaitTimeout: 10 * time.Second,
 
+	Lock: LockConfig{
+		Lock:   "",
+		Expiry: 30 * time.Second,
+	},
+
+	History: HistoryConfig{
+		Size:    20,
+		Persist: false,
+		Redis:   "",
+	},
+
 	Log: LogConfig{
 		ErrorFilename: "./logs/cron.log",
 		MaxSize:       100,
@@ -16,11 +30,42 @@ var DefaultConfig = Config{
 }
 
 type Config struct {
-	ShutdownWaitTimeout time.Duration `mapstructure:"shutdown_wait_timeout"`
+	Logger              string        `mapstructure:"logger"`                                            // Logger 记录任务调度信息的nlog实例scope 为空时使用默认实例
+	Timeout             time.Duration `mapstructure:"timeout" validate:"gte=0"`                          // Timeout 命名任务默认执行超时时间 超时后取消任务context 0表示不限制
+	Overlap             string        `mapstructure:"overlap" validate:"oneof=allow skip delay replace"` // Overlap 命名任务默认重叠策略
+	ShutdownWaitTimeout time.Duration `mapstructure:"shutdown_wait_timeout" validate:"gte=0"`
+
+	Jobs map[string]JobConfig `mapstructure:"jobs" validate:"dive"` // Jobs 按任务名覆盖命名任务的声明 变更后自动重新调度
+
+	Lock LockConfig `mapstructure:"lock"`
+
+	History HistoryConfig `mapstructure:"history"`
 
 	Log LogConfig `mapstructure:"log"`
 }
 
+// JobConfig 命名任务配置 未设置的项使用代码中的声明
+type JobConfig struct {
+	Spec     string        `mapstructure:"spec"`                                                        // Spec 调度表达式 支持秒级
+	Timezone string        `mapstructure:"timezone" validate:"omitempty,timezone"`                      // Timezone 调度表达式使用的时区 如Asia/Shanghai 为空时使用服务器本地时区
+	Enabled  *bool         `mapstructure:"enabled"`                                                     // Enabled 是否启用 为false时不调度
+	Jitter   time.Duration `mapstructure:"jitter" validate:"gte=0"`                                     // Jitter 每次调度随机延迟[0, Jitter)后执行 分散同一时刻触发的任务
+	Overlap  string        `mapstructure:"overlap" validate:"omitempty,oneof=allow skip delay replace"` // Overlap 重叠策略
+}
+
+// LockConfig 集群互斥锁配置 仅对AddExclusive调度或WithExclusive声明的任务生效
+type LockConfig struct {
+	Lock   string        `mapstructure:"lock"`                   // Lock 使用的lock实例scope 为空时使用默认实例
+	Expiry time.Duration `mapstructure:"expiry" validate:"gt=0"` // Expiry 锁过期时间 执行期间每隔Expiry/3自动续期
+}
+
+// HistoryConfig 执行历史配置 仅记录通过Register注册的任务
+type HistoryConfig struct {
+	Size    int    `mapstructure:"size" validate:"gt=0"` // Size 每个任务保留的执行记录条数
+	Persist bool   `mapstructure:"persist"`              // Persist 是否持久化到redis 开启后可查询全部副本的执行记录
+	Redis   string `mapstructure:"redis"`                // Redis 持久化使用的redis实例scope 为空时使用默认实例
+}
+
 type LogConfig struct {
 	ErrorFilename string `mapstructure:"error_filename"` // ErrorFilename 日志文件名
 	MaxSize       int    `mapstructure:"max_size"`       // MaxSize 触发日志切割大小 单位 MB
@@ -1,23 +1,33 @@
 package crontab
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"reflect"
 	"runtime"
-	"time"
+	"strings"
+	"sync"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/zjutjh/mygo/config"
 	"github.com/zjutjh/mygo/feishu"
 	"github.com/zjutjh/mygo/foundation/kernel"
+	"github.com/zjutjh/mygo/nlog"
 )
 
+func init() {
+	kernel.RegisterOptionalConfig("cron", DefaultConfig)
+	kernel.OnReset("crontab", reset)
+}
+
 // CommandRegister 启动定时任务命令注册
 func CommandRegister(jobRegister func(c *cron.Cron)) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
@@ -26,16 +36,61 @@ func CommandRegister(jobRegister func(c *cron.Cron)) func(cmd *cobra.Command, ar
 	}
 }
 
-// Run 启动定时任务
+// Run 启动定时任务 并阻塞至收到结束信号后优雅关闭
+// 返回 (或启动失败退出进程) 前关闭全部已引导资源
 func Run(jobRegister func(c *cron.Cron)) {
+	defer closeResources()
+
+	e, err := NewEngine(jobRegister)
+	if err != nil {
+		fmt.Fprintln(kernel.Output(), err)
+		closeResources()
+		os.Exit(1)
+	}
+
+	// 启动cron
+	e.Start()
+
+	// 监听并等待关闭服务
+	kernel.ListenStop(func() error {
+		if err := e.Stop(context.Background()); err != nil {
+			return err
+		}
+		fmt.Fprintln(kernel.Output(), "Cron关闭完成")
+		return nil
+	})
+}
+
+// closeResources 关闭全部已引导资源 (日志最后关闭)
+func closeResources() {
+	if err := kernel.Shutdown(context.Background()); err != nil {
+		fmt.Fprintln(kernel.Output(), "关闭资源错误:", err)
+	}
+}
+
+// Engine 定时任务引擎
+type Engine struct {
+	conf   Config
+	cron   *cron.Cron
+	logger cron.Logger
+
+	// ctx 命名任务执行context的父context 停止引擎时以ErrShutdown取消 重新启动时重建
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	mu     sync.Mutex
+
+	// states 命名任务的运行状态
+	states map[string]*jobState
+}
+
+// NewEngine 按config.yaml[cron]创建定时任务引擎并注册任务 不启动调度
+func NewEngine(jobRegister func(c *cron.Cron)) (*Engine, error) {
 	// 获取配置
-	conf := DefaultConfig
-	config.Pick().UnmarshalKey("cron", &conf)
+	conf := loadConfig()
 
 	_, err := os.OpenFile(conf.Log.ErrorFilename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		fmt.Fprintln(os.Stdout, "初始化cron引擎日志错误:", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("初始化cron引擎日志错误: %w", err)
 	}
 	ew := &lumberjack.Logger{
 		Filename:   conf.Log.ErrorFilename,
@@ -46,31 +101,110 @@ func Run(jobRegister func(c *cron.Cron)) {
 		Compress:   conf.Log.Compress,
 	}
 	logger := cron.PrintfLogger(log.New(ew, "\ncron: ", log.LstdFlags))
+	// 日志文件在其他资源关闭后最后关闭
+	kernel.OnCloseLast("crontab:log", func(context.Context) error {
+		return ew.Close()
+	})
+	// 收到SIGHUP时关闭日志文件句柄 下次写入时重新打开
+	kernel.OnSignal(kernel.SignalReload, "crontab:log", func() error {
+		return ew.Close()
+	})
 
 	// 初始化cron实例
 	c := cron.New(cron.WithSeconds(), cron.WithChain(Recover(logger)))
 
-	// 启动cron
-	c.Start()
-
 	// 注册任务
-	jobRegister(c)
-
-	// 监听并等待关闭服务
-	kernel.ListenStop(func() error {
-		ctx := c.Stop()
-		timer := time.NewTimer(conf.ShutdownWaitTimeout)
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			fmt.Fprintln(os.Stdout, "Cron关闭完成")
+	if jobRegister != nil {
+		jobRegister(c)
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	e := &Engine{conf: conf, cron: c, logger: logger, ctx: ctx, cancel: cancel, states: map[string]*jobState{}}
+	registered := Jobs()
+	for _, j := range registered {
+		if err := e.schedule(j); err != nil {
+			return nil, err
+		}
+	}
+	// 配置变更时重新调度命名任务
+	if len(registered) > 0 {
+		nl := nlog.Pick(conf.Logger)
+		config.OnChange("", "cron", func(*viper.Viper) error {
+			e.reload(nl)
 			return nil
-		case <-timer.C:
-			return errors.New("cron等待优雅处理超时, 强制关闭")
+		})
+	}
+
+	return e, nil
+}
+
+// loadConfig 读取config.yaml[cron] 未配置的项使用默认值
+func loadConfig() Config {
+	conf := DefaultConfig
+	config.Pick().UnmarshalKey("cron", &conf)
+	return conf
+}
+
+// Cron 获取底层cron实例
+func (e *Engine) Cron() *cron.Cron {
+	return e.cron
+}
+
+// Start 在后台开始调度
+func (e *Engine) Start() {
+	e.mu.Lock()
+	if e.ctx.Err() != nil {
+		e.ctx, e.cancel = context.WithCancelCause(context.Background())
+	}
+	e.mu.Unlock()
+	e.cron.Start()
+}
+
+// context 获取命名任务执行context的父context
+func (e *Engine) context() context.Context {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.ctx
+}
+
+// Run 开始调度并阻塞 ctx取消时停止调度并等待执行中的任务完成
+func (e *Engine) Run(ctx context.Context) error {
+	e.Start()
+	<-ctx.Done()
+	return e.Stop(context.Background())
+}
+
+// Stop 停止调度 取消执行中的命名任务context并等待任务完成 等待时长受ctx与配置的shutdown_wait_timeout共同约束
+func (e *Engine) Stop(ctx context.Context) error {
+	done := e.cron.Stop()
+	e.mu.Lock()
+	e.cancel(ErrShutdown)
+	e.mu.Unlock()
+	ctx, cancel := context.WithTimeout(ctx, e.conf.ShutdownWaitTimeout)
+	defer cancel()
+	select {
+	case <-done.Done():
+		return nil
+	case <-ctx.Done():
+		return errors.New("cron等待优雅处理超时, 强制关闭")
+	}
+}
+
+// jobName 任务标识 命名任务为任务名 函数任务 (如AddFunc添加的) 为函数名 其他任务为类型名
+func jobName(j cron.Job) string {
+	if n, ok := j.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	if v := reflect.ValueOf(j); v.Kind() == reflect.Func && !v.IsNil() {
+		if f := runtime.FuncForPC(v.Pointer()); f != nil {
+			// 去除包路径 如github.com/org/app/job.SyncUsers -> job.SyncUsers
+			name := f.Name()
+			return name[strings.LastIndex(name, "/")+1:]
 		}
-	})
+	}
+	return fmt.Sprintf("%T", j)
 }
 
+// Recover 捕获任务panic 记录日志并发送飞书报警
 func Recover(logger cron.Logger) cron.JobWrapper {
 	return func(j cron.Job) cron.Job {
 		return cron.FuncJob(func() {
@@ -83,21 +217,32 @@ func Recover(logger cron.Logger) cron.JobWrapper {
 					if !ok {
 						err = fmt.Errorf("%v", r)
 					}
-					logger.Error(err, "panic", "stack", "...\n"+string(buf))
-					// 发送报警
-					go func() {
-						defer func() {
-							if err2 := recover(); err2 != nil {
-								log.Println("请求飞书Bot发送报警发生了Panic", err2)
-							}
-						}()
-						title := fmt.Sprintf("[%s]CronJob Panic!!!", config.AppName())
-						message := fmt.Sprintf("请注意: CronJob[%#v]发生了Panic!!!\nPanic: %#v", j, r)
-						feishu.Pick().Send(title, message)
-					}()
+					alert(logger, err,
+						fmt.Sprintf("[%s]CronJob Panic!!!", config.AppName()),
+						fmt.Sprintf("请注意: CronJob[%s]发生了Panic!!!\nPanic: %#v", jobName(j), r),
+						"panic", "job", jobName(j), "stack", "...\n"+string(buf),
+					)
 				}
 			}()
 			j.Run()
 		})
 	}
 }
+
+// alert 记录任务异常日志并发送飞书报警
+func alert(logger cron.Logger, err error, title, message string, msg string, keysAndValues ...any) {
+	logger.Error(err, msg, keysAndValues...)
+	notify(title, message)
+}
+
+// notify 异步发送飞书报警
+func notify(title, message string) {
+	go func() {
+		defer func() {
+			if err2 := recover(); err2 != nil {
+				log.Println("请求飞书Bot发送报警发生了Panic", err2)
+			}
+		}()
+		feishu.Pick().Send(title, message)
+	}()
+}
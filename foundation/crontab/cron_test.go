@@ -0,0 +1,34 @@
+package crontab
+
+import (
+	"testing"
+
+	"github.com/robfig/cron/v3"
+)
+
+func syncUsers() {}
+
+type reportJob struct{ day int }
+
+func (reportJob) Run() {}
+
+func TestJobName(t *testing.T) {
+	tests := []struct {
+		name string
+		job  cron.Job
+		want string
+	}{
+		{"命名任务", namedJob{name: "sync_users", fn: syncUsers}, "sync_users"},
+		{"AddFunc添加的函数", cron.FuncJob(syncUsers), "crontab.syncUsers"},
+		{"匿名函数", cron.FuncJob(func() {}), "crontab.TestJobName.func1"},
+		{"结构体任务", reportJob{day: 1}, "crontab.reportJob"},
+		{"空函数", cron.FuncJob(nil), "cron.FuncJob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jobName(tt.job); got != tt.want {
+				t.Fatalf("jobName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package crontab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redsync/redsync/v4"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/lock"
+	"github.com/zjutjh/mygo/nlog"
+)
+
+// ErrTaken 本次调度已由其他副本执行
+var ErrTaken = errors.New("本次调度已由其他副本执行")
+
+// exclusiveKey 任务某次调度的集群互斥锁键 形如mygo:cron:{app}:{name}:{调度时间unix秒}
+func exclusiveKey(name string, tick time.Time) string {
+	return fmt.Sprintf("mygo:cron:%s:%s:%d", config.AppName(), name, tick.Unix())
+}
+
+// scheduledTick 条目本次触发对应的调度时间 (Entry.Prev) 各副本按同一调度表达式得到相同的调度时间
+// 调度器在返回条目快照前已更新Prev 条目已被移除 (重新调度) 时退回当前时间截断到秒
+func scheduledTick(c *cron.Cron, id cron.EntryID) time.Time {
+	if prev := c.Entry(id).Prev; !prev.IsZero() {
+		return prev
+	}
+	return time.Now().Truncate(time.Second)
+}
+
+// AddExclusive 以集群互斥方式调度任务 多副本部署时同一次调度仅由抢到锁的一个副本执行 其余副本记录持有者并跳过
+// 使用config.yaml[cron.lock]指定的lock实例 执行期间每隔Expiry/3自动续期
+// 执行结束后不主动释放锁 由其自然过期 避免时钟略慢的副本重复执行同一次调度
+// 锁键取自本次触发的调度时间 要求各副本时钟偏差小于调度间隔
+// 调度表达式无效或lock实例未引导时返回错误
+func AddExclusive(c *cron.Cron, name, spec string, j cron.Job) (cron.EntryID, error) {
+	sched, err := parser.Parse(spec)
+	if err != nil {
+		return 0, fmt.Errorf("调度定时任务[%s]错误: %w", name, err)
+	}
+	conf := loadConfig()
+	g, err := newExclusiveGuard(name, conf, nlog.Pick(conf.Logger))
+	if err != nil {
+		return 0, err
+	}
+	var id atomic.Int64
+	eid := c.Schedule(sched, namedJob{name: name, fn: func() {
+		// 已由其他副本执行 (ErrTaken) 或获取锁错误 acquire均已记录 后者同时报警
+		release, err := g.acquire(scheduledTick(c, cron.EntryID(id.Load())), nil)
+		if err != nil {
+			return
+		}
+		defer release()
+		j.Run()
+	}})
+	id.Store(int64(eid))
+	return eid, nil
+}
+
+// exclusiveGuard 任务的集群互斥锁
+type exclusiveGuard struct {
+	name   string
+	scope  string
+	expiry time.Duration
+	logger *logrus.Logger
+}
+
+// newExclusiveGuard 创建任务的集群互斥锁 lock实例未引导时返回错误
+func newExclusiveGuard(name string, conf Config, logger *logrus.Logger) (*exclusiveGuard, error) {
+	scope := conf.Lock.Lock
+	if scope == "" {
+		scope = "lock" // lock默认实例scope
+	}
+	if !lock.Exist(scope) {
+		return nil, fmt.Errorf("定时任务[%s]声明了集群互斥执行, 但lock实例[%s]未引导", name, scope)
+	}
+	return &exclusiveGuard{name: name, scope: scope, expiry: conf.Lock.Expiry, logger: logger}, nil
+}
+
+// acquire 抢占tick时刻的调度 成功后每隔expiry/3自动续期 续期失败时以ErrLockLost调用cancel (不为nil时)
+// 返回的release用于停止续期 已由其他副本执行时记录持有者并返回ErrTaken
+// 其他错误 (如redis超时) 记录日志并发送飞书报警
+func (g *exclusiveGuard) acquire(tick time.Time, cancel context.CancelCauseFunc) (release func(), err error) {
+	key := exclusiveKey(g.name, tick)
+	m := lock.Pick(g.scope).NewMutex(key,
+		redsync.WithExpiry(g.expiry),
+		redsync.WithTries(1),
+		redsync.WithGenValueFunc(lock.OwnerValue),
+	)
+	if err := m.TryLock(); err != nil {
+		var taken *redsync.ErrTaken
+		if !errors.Is(err, redsync.ErrFailed) && !errors.As(err, &taken) {
+			// redis超时、网络错误等 本次调度在所有副本上都可能未执行 需报警
+			g.logger.WithError(err).Errorf("获取定时任务[%s]集群互斥锁[%s]错误, 跳过本次执行", g.name, key)
+			notify(
+				fmt.Sprintf("[%s]CronJob Lock Error!!!", config.AppName()),
+				fmt.Sprintf("请注意: CronJob[%s]获取集群互斥锁[%s]错误, 已跳过本次执行!!!\nError: %s", g.name, key, err),
+			)
+			return nil, fmt.Errorf("获取集群互斥锁[%s]错误: %w", key, err)
+		}
+		owner, err := lock.Owner(context.Background(), key, g.scope)
+		if err != nil {
+			owner = "未知"
+		}
+		g.logger.WithField("owner", owner).Infof("定时任务[%s]本次调度已由[%s]执行, 跳过", g.name, owner)
+		return nil, fmt.Errorf("%w: %s", ErrTaken, owner)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(g.expiry / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if ok, err := m.Extend(); !ok || err != nil {
+					g.logger.WithError(err).Errorf("定时任务[%s]集群互斥锁[%s]续期失败, 其他副本可能重复执行", g.name, key)
+					if cancel != nil {
+						cancel(ErrLockLost)
+					}
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}, nil
+}
@@ -0,0 +1,104 @@
+package crontab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/nedis"
+)
+
+// 任务执行结果
+const (
+	OutcomeSuccess  = "success"  // OutcomeSuccess 执行成功
+	OutcomeError    = "error"    // OutcomeError 执行返回错误
+	OutcomePanic    = "panic"    // OutcomePanic 执行发生panic
+	OutcomeTimeout  = "timeout"  // OutcomeTimeout 执行超时 任务context已取消
+	OutcomeCanceled = "canceled" // OutcomeCanceled 引擎停止或集群互斥锁丢失 任务context已取消
+	OutcomeSkipped  = "skipped"  // OutcomeSkipped 未执行
+)
+
+// Record 任务单次执行记录
+type Record struct {
+	Job        string    `json:"job"`
+	Host       string    `json:"host"`
+	Start      time.Time `json:"start"`
+	Duration   string    `json:"duration"`
+	DurationMs int64     `json:"duration_ms"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+	Next       time.Time `json:"next"`
+}
+
+var (
+	hostname, _ = os.Hostname()
+
+	histories   = map[string][]Record{}
+	historiesMu sync.RWMutex
+)
+
+// historyKey 任务执行历史在redis中的键
+func historyKey(name string) string {
+	return fmt.Sprintf("mygo:cron:%s:history:%s", config.AppName(), name)
+}
+
+// saveRecord 保存执行记录 始终保存到内存 开启持久化时同时写入redis
+func saveRecord(conf HistoryConfig, r Record) error {
+	historiesMu.Lock()
+	list := append([]Record{r}, histories[r.Job]...)
+	if len(list) > conf.Size {
+		list = list[:conf.Size]
+	}
+	histories[r.Job] = list
+	historiesMu.Unlock()
+
+	if !conf.Persist {
+		return nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	key := historyKey(r.Job)
+	_, err = nedis.Pick(conf.Redis).Pipelined(context.Background(), func(p redis.Pipeliner) error {
+		p.LPush(context.Background(), key, data)
+		p.LTrim(context.Background(), key, 0, int64(conf.Size-1))
+		return nil
+	})
+	return err
+}
+
+// History 获取任务最近的执行记录 按开始时间倒序 最多config.yaml[cron.history.size]条
+// 开启持久化时从redis读取 包含全部副本的执行记录 否则仅包含本进程的执行记录
+func History(ctx context.Context, name string) ([]Record, error) {
+	conf := loadConfig().History
+	if !conf.Persist {
+		historiesMu.RLock()
+		defer historiesMu.RUnlock()
+		list := histories[name]
+		if len(list) > conf.Size {
+			list = list[:conf.Size]
+		}
+		return append([]Record{}, list...), nil
+	}
+
+	items, err := nedis.Pick(conf.Redis).LRange(ctx, historyKey(name), 0, int64(conf.Size-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取定时任务[%s]执行历史错误: %w", name, err)
+	}
+	list := make([]Record, 0, len(items))
+	for _, item := range items {
+		var r Record
+		if err := json.Unmarshal([]byte(item), &r); err != nil {
+			return nil, fmt.Errorf("解析定时任务[%s]执行历史错误: %w", name, err)
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
@@ -0,0 +1,155 @@
+package crontab
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Job 命名定时任务
+type Job struct {
+	Name        string `json:"name"`        // Name 任务名 不可重复
+	Description string `json:"description"` // Description 任务说明
+	Spec        string `json:"spec"`        // Spec 调度表达式 支持秒级 可被配置覆盖
+	Exclusive   bool   `json:"exclusive"`   // Exclusive 是否集群互斥执行
+
+	run     func(ctx context.Context) error
+	timeout *time.Duration
+	overlap Overlap
+	// state 本进程引擎中的运行状态 未被引擎调度时为nil
+	state *jobState
+}
+
+// JobOption 任务选项
+type JobOption func(*Job)
+
+// WithDescription 设置任务说明
+func WithDescription(desc string) JobOption {
+	return func(j *Job) {
+		j.Description = desc
+	}
+}
+
+// WithTimeout 设置任务执行超时时间 覆盖config.yaml[cron.timeout] 0表示不限制
+// 超时后取消任务context并报警
+func WithTimeout(timeout time.Duration) JobOption {
+	return func(j *Job) {
+		j.timeout = &timeout
+	}
+}
+
+// WithOverlap 设置重叠策略 优先级低于config.yaml[cron.jobs.{name}.overlap] 高于config.yaml[cron.overlap]
+func WithOverlap(overlap Overlap) JobOption {
+	return func(j *Job) {
+		j.overlap = overlap
+	}
+}
+
+// WithExclusive 集群互斥执行 同一次调度仅由一个副本执行 见AddExclusive
+func WithExclusive() JobOption {
+	return func(j *Job) {
+		j.Exclusive = true
+	}
+}
+
+var (
+	jobs   []*Job
+	jobsMu sync.RWMutex
+)
+
+// Register 注册命名定时任务 同名任务会被替换
+// 已注册的任务在创建引擎 (NewEngine) 时按注册顺序调度 每次执行均记录到执行历史
+// config.yaml[cron.jobs.{name}]可覆盖调度表达式、时区、启用状态、随机延迟与重叠策略 spec为空时须在配置中设置
+// run的ctx在任务超时、引擎停止或集群互斥锁续期失败时取消 任务应及时返回
+func Register(name, spec string, run func(ctx context.Context) error, opts ...JobOption) {
+	j := &Job{Name: name, Spec: spec, run: run}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	for i, old := range jobs {
+		if old.Name == name {
+			jobs[i] = j
+			return
+		}
+	}
+	jobs = append(jobs, j)
+}
+
+// Jobs 获取全部已注册任务
+func Jobs() []Job {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+	list := make([]Job, 0, len(jobs))
+	for _, j := range jobs {
+		list = append(list, *j)
+	}
+	return list
+}
+
+// lookup 按任务名获取已注册任务
+func lookup(name string) (Job, bool) {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+	for _, j := range jobs {
+		if j.Name == name {
+			return *j, true
+		}
+	}
+	return Job{}, false
+}
+
+// setState 记录任务在本进程引擎中的运行状态
+func setState(name string, state *jobState) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	for _, j := range jobs {
+		if j.Name == name {
+			j.state = state
+		}
+	}
+}
+
+// reset 重置任务注册表与执行历史 已注册的任务 (不含运行状态) 复制到重置后的注册表 见kernel.Reset
+func reset() (restore func()) {
+	jobsMu.Lock()
+	prevJobs := jobs
+	jobs = make([]*Job, 0, len(prevJobs))
+	for _, j := range prevJobs {
+		cp := *j
+		cp.state = nil
+		jobs = append(jobs, &cp)
+	}
+	jobsMu.Unlock()
+
+	historiesMu.Lock()
+	prevHistories := histories
+	histories = map[string][]Record{}
+	historiesMu.Unlock()
+
+	return func() {
+		jobsMu.Lock()
+		jobs = prevJobs
+		jobsMu.Unlock()
+
+		historiesMu.Lock()
+		histories = prevHistories
+		historiesMu.Unlock()
+	}
+}
+
+// namedJob 带任务名的cron.Job 用于panic报警中标识任务
+type namedJob struct {
+	name string
+	fn   func()
+}
+
+func (j namedJob) Run() {
+	j.fn()
+}
+
+func (j namedJob) Name() string {
+	return j.name
+}
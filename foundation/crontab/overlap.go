@@ -0,0 +1,99 @@
+package crontab
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrReplaced 任务被新一次调度取代 replace策略下上次执行的context以此为原因取消
+var ErrReplaced = errors.New("定时任务被新一次调度取代")
+
+// Overlap 重叠策略 任务执行时长超过调度间隔时的处理方式
+type Overlap string
+
+const (
+	OverlapAllow   Overlap = "allow"   // OverlapAllow 允许同时执行多次
+	OverlapSkip    Overlap = "skip"    // OverlapSkip 上次执行未结束时跳过本次调度
+	OverlapDelay   Overlap = "delay"   // OverlapDelay 上次执行结束后再执行本次调度
+	OverlapReplace Overlap = "replace" // OverlapReplace 取消上次执行 待其结束后执行本次调度
+)
+
+// overlapGuard 按重叠策略控制任务的并发执行
+type overlapGuard struct {
+	running atomic.Bool // skip策略 是否有执行中的任务
+	mu      sync.Mutex  // delay/replace策略 串行执行
+
+	cancelMu sync.Mutex
+	cancel   context.CancelCauseFunc // replace策略 执行中任务的context取消函数
+	replace  bool                    // replace策略 执行中任务尚未设置cancel时的待取消标记
+
+	skipped  atomic.Int64
+	delayed  atomic.Int64
+	replaced atomic.Int64
+}
+
+// enter 按重叠策略进入执行 返回false表示跳过本次调度 进入成功时须在执行结束后调用leave
+// 配置变更可能修改策略 leave与track须使用与enter相同的策略
+func (g *overlapGuard) enter(name string, overlap Overlap, logger *logrus.Logger) bool {
+	switch overlap {
+	case OverlapSkip:
+		if !g.running.CompareAndSwap(false, true) {
+			n := g.skipped.Add(1)
+			logger.WithField("skipped", n).Warnf("定时任务[%s]上次执行尚未结束, 跳过本次调度", name)
+			return false
+		}
+	case OverlapDelay:
+		if !g.mu.TryLock() {
+			n := g.delayed.Add(1)
+			logger.WithField("delayed", n).Warnf("定时任务[%s]上次执行尚未结束, 延迟至其结束后执行", name)
+			g.mu.Lock()
+		}
+	case OverlapReplace:
+		if !g.mu.TryLock() {
+			n := g.replaced.Add(1)
+			logger.WithField("replaced", n).Warnf("定时任务[%s]上次执行尚未结束, 取消上次执行", name)
+			g.cancelMu.Lock()
+			if g.cancel != nil {
+				g.cancel(ErrReplaced)
+			} else {
+				g.replace = true
+			}
+			g.cancelMu.Unlock()
+			g.mu.Lock()
+		}
+	}
+	return true
+}
+
+// leave 结束执行
+func (g *overlapGuard) leave(overlap Overlap) {
+	switch overlap {
+	case OverlapSkip:
+		g.running.Store(false)
+	case OverlapDelay, OverlapReplace:
+		g.mu.Unlock()
+	}
+}
+
+// track 记录执行中任务的context取消函数 供replace策略取消 返回的函数用于执行结束后清除
+func (g *overlapGuard) track(overlap Overlap, cancel context.CancelCauseFunc) (untrack func()) {
+	if overlap != OverlapReplace {
+		return func() {}
+	}
+	g.cancelMu.Lock()
+	defer g.cancelMu.Unlock()
+	g.cancel = cancel
+	if g.replace {
+		g.replace = false
+		cancel(ErrReplaced)
+	}
+	return func() {
+		g.cancelMu.Lock()
+		defer g.cancelMu.Unlock()
+		g.cancel = nil
+	}
+}
@@ -0,0 +1,272 @@
+package crontab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/nlog"
+)
+
+var (
+	// ErrTimeout 任务执行超时 任务context以此为原因取消
+	ErrTimeout = errors.New("定时任务执行超时")
+	// ErrShutdown 引擎停止 执行中的任务context以此为原因取消
+	ErrShutdown = errors.New("定时任务引擎停止")
+	// ErrLockLost 执行期间集群互斥锁续期失败 任务context以此为原因取消
+	ErrLockLost = errors.New("定时任务集群互斥锁续期失败")
+)
+
+// parser 与cron.WithSeconds一致的调度表达式解析器
+var parser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// plan 命名任务生效的调度设置 由代码声明与config.yaml[cron]合并而来 配置优先
+type plan struct {
+	spec     string
+	timezone string
+	enabled  bool
+	jitter   time.Duration
+	overlap  Overlap
+	timeout  time.Duration
+}
+
+// planOf 合并代码声明与配置
+func planOf(j Job, conf Config) plan {
+	p := plan{spec: j.Spec, enabled: true, overlap: Overlap(conf.Overlap), timeout: conf.Timeout}
+	if j.overlap != "" {
+		p.overlap = j.overlap
+	}
+	if j.timeout != nil {
+		p.timeout = *j.timeout
+	}
+	jc := conf.Jobs[j.Name]
+	if jc.Spec != "" {
+		p.spec = jc.Spec
+	}
+	if jc.Timezone != "" {
+		p.timezone = jc.Timezone
+	}
+	if jc.Enabled != nil {
+		p.enabled = *jc.Enabled
+	}
+	if jc.Jitter > 0 {
+		p.jitter = jc.Jitter
+	}
+	if jc.Overlap != "" {
+		p.overlap = Overlap(jc.Overlap)
+	}
+	return p
+}
+
+// cronSpec 带时区的调度表达式
+func (p plan) cronSpec() string {
+	if p.timezone != "" {
+		return "CRON_TZ=" + p.timezone + " " + p.spec
+	}
+	return p.spec
+}
+
+// jobState 命名任务在本进程引擎中的运行状态
+type jobState struct {
+	overlapGuard
+
+	job  cron.Job
+	next func() time.Time
+
+	mu   sync.RWMutex
+	plan plan
+	id   cron.EntryID // 未启用时为0
+}
+
+func (s *jobState) current() (plan, cron.EntryID) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.plan, s.id
+}
+
+// schedule 调度已注册的命名任务 每次执行记录到执行历史
+func (e *Engine) schedule(j Job) error {
+	logger := nlog.Pick(e.conf.Logger)
+	var guard *exclusiveGuard
+	if j.Exclusive {
+		var err error
+		if guard, err = newExclusiveGuard(j.Name, e.conf, logger); err != nil {
+			return err
+		}
+	}
+	state := &jobState{}
+	state.next = func() time.Time {
+		_, id := state.current()
+		return e.cron.Entry(id).Next
+	}
+
+	run := func() {
+		p, id := state.current()
+		tick := scheduledTick(e.cron, id)
+
+		// 随机延迟 分散同一时刻触发的任务
+		if p.jitter > 0 {
+			select {
+			case <-time.After(rand.N(p.jitter)):
+			case <-e.context().Done():
+				return
+			}
+		}
+
+		if !state.enter(j.Name, p.overlap, logger) {
+			return
+		}
+		defer state.leave(p.overlap)
+		// 引擎停止后不再执行排队等待的调度
+		if e.context().Err() != nil {
+			return
+		}
+
+		r := Record{Job: j.Name, Host: hostname, Start: time.Now()}
+		finish := func() {
+			d := time.Since(r.Start)
+			r.Duration = d.String()
+			r.DurationMs = d.Milliseconds()
+			r.Next = state.next()
+			if err := saveRecord(e.conf.History, r); err != nil {
+				logger.WithError(err).Errorf("保存定时任务[%s]执行记录错误", j.Name)
+			}
+		}
+
+		// 任务context 引擎停止、超时、被新一次调度取代或集群互斥锁续期失败时取消
+		ctx, cancel := context.WithCancelCause(e.context())
+		defer cancel(nil)
+		defer state.track(p.overlap, cancel)()
+		if p.timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithTimeoutCause(ctx, p.timeout, fmt.Errorf("%w[%s]", ErrTimeout, p.timeout))
+			defer cancelTimeout()
+			// 超时立即报警 不等待任务返回
+			stop := context.AfterFunc(ctx, func() {
+				if cause := context.Cause(ctx); errors.Is(cause, ErrTimeout) {
+					alert(e.logger, cause,
+						fmt.Sprintf("[%s]CronJob Timeout!!!", config.AppName()),
+						fmt.Sprintf("请注意: CronJob[%s]执行超时[%s]!!!", j.Name, p.timeout),
+						"timeout", "job", j.Name,
+					)
+				}
+			})
+			defer stop()
+		}
+
+		if guard != nil {
+			release, err := guard.acquire(tick, cancel)
+			if errors.Is(err, ErrTaken) {
+				return
+			}
+			if err != nil {
+				r.Outcome = OutcomeSkipped
+				r.Error = err.Error()
+				finish()
+				return
+			}
+			defer release()
+		}
+
+		// 记录panic后继续向上抛出 由Recover记录日志并报警
+		defer func() {
+			if pnc := recover(); pnc != nil {
+				r.Outcome = OutcomePanic
+				r.Error = fmt.Sprint(pnc)
+				finish()
+				panic(pnc)
+			}
+		}()
+		if err := j.run(ctx); err != nil {
+			r.Outcome = OutcomeError
+			// 任务context被取消时附加取消原因
+			if cause := context.Cause(ctx); cause != nil {
+				r.Outcome = OutcomeCanceled
+				if errors.Is(cause, ErrTimeout) {
+					r.Outcome = OutcomeTimeout
+				}
+				if !errors.Is(err, cause) {
+					err = fmt.Errorf("%w (任务已取消: %w)", err, cause)
+				}
+			}
+			r.Error = err.Error()
+			logger.WithError(err).Errorf("定时任务[%s]执行错误", j.Name)
+		} else if cause := context.Cause(ctx); errors.Is(cause, ErrTimeout) {
+			// 未响应context取消但执行超时的任务同样记为超时
+			r.Outcome = OutcomeTimeout
+			r.Error = cause.Error()
+		} else {
+			r.Outcome = OutcomeSuccess
+		}
+		finish()
+	}
+
+	state.job = namedJob{name: j.Name, fn: run}
+	if err := e.apply(j.Name, state, planOf(j, e.conf)); err != nil {
+		return err
+	}
+	e.states[j.Name] = state
+	setState(j.Name, state)
+	return nil
+}
+
+// apply 按调度设置 (重新) 调度任务 调度表达式无效时保留原调度
+func (e *Engine) apply(name string, state *jobState, p plan) error {
+	var sched cron.Schedule
+	if p.enabled {
+		if p.spec == "" {
+			return fmt.Errorf("调度定时任务[%s]错误: 未设置调度表达式 (代码声明或config.yaml[cron.jobs.%s.spec])", name, name)
+		}
+		var err error
+		if sched, err = parser.Parse(p.cronSpec()); err != nil {
+			return fmt.Errorf("调度定时任务[%s]错误: %w", name, err)
+		}
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.id != 0 {
+		e.cron.Remove(state.id)
+		state.id = 0
+	}
+	if sched != nil {
+		state.id = e.cron.Schedule(sched, state.job)
+	}
+	state.plan = p
+	return nil
+}
+
+// reload 配置变更时按最新的config.yaml[cron]重新调度 仅处理调度设置发生变化的命名任务
+// 执行中的任务不受影响 新的调度设置从下一次调度开始生效
+func (e *Engine) reload(logger *logrus.Logger) {
+	conf := loadConfig()
+	for _, j := range Jobs() {
+		state, ok := e.states[j.Name]
+		if !ok {
+			continue
+		}
+		old, _ := state.current()
+		p := planOf(j, conf)
+		if p == old {
+			continue
+		}
+		if err := e.apply(j.Name, state, p); err != nil {
+			logger.WithError(err).Errorf("定时任务[%s]按新配置重新调度失败, 保留原调度", j.Name)
+			continue
+		}
+		logger.WithFields(logrus.Fields{
+			"spec":    p.cronSpec(),
+			"enabled": p.enabled,
+			"jitter":  p.jitter.String(),
+			"overlap": p.overlap,
+			"timeout": p.timeout.String(),
+		}).Infof("定时任务[%s]已按新配置重新调度", j.Name)
+	}
+}
@@ -9,6 +9,13 @@ var DefaultConfig = Config{
 
 	Pprof: false,
 
+	Health: HealthConfig{
+		Enable:        true,
+		LivenessPath:  "/healthz",
+		ReadinessPath: "/readyz",
+		ShutdownDelay: 0,
+	},
+
 	Log: LogConfig{
 		AccessFilename: "./logs/access.log",
 		ErrorFilename:  "./logs/error.log",
@@ -25,12 +32,14 @@ var DefaultConfig = Config{
 }
 
 type Config struct {
-	Addr string `mapstructure:"addr"`
+	Addr string `mapstructure:"addr" validate:"required"`
 
-	ShutdownWaitTimeout time.Duration `mapstructure:"shutdown_wait_timeout"`
+	ShutdownWaitTimeout time.Duration `mapstructure:"shutdown_wait_timeout" validate:"gte=0"`
 
 	Pprof bool `mapstructure:"pprof"`
 
+	Health HealthConfig `mapstructure:"health"`
+
 	Log LogConfig `mapstructure:"log"`
 
 	Gin GinConfig `mapstructure:"gin"`
@@ -46,6 +55,13 @@ type LogConfig struct {
 	Compress       bool   `mapstructure:"compress"`        // Compress 日志切割后是否对归档文件进行压缩
 }
 
+type HealthConfig struct {
+	Enable        bool          `mapstructure:"enable"`                          // Enable 是否注册存活与就绪探针路由
+	LivenessPath  string        `mapstructure:"liveness_path"`                   // LivenessPath 存活探针路径
+	ReadinessPath string        `mapstructure:"readiness_path"`                  // ReadinessPath 就绪探针路径
+	ShutdownDelay time.Duration `mapstructure:"shutdown_delay" validate:"gte=0"` // ShutdownDelay 就绪探针失败后等待多久再开始关闭 便于负载均衡摘除流量
+}
+
 // Gin配置 有需要时再补充
 type GinConfig struct {
 	UseH2C bool `mapstructure:"use_h2c"`
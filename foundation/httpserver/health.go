@@ -0,0 +1,34 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zjutjh/mygo/foundation/kernel"
+)
+
+// registerHealthRoutes 注册存活与就绪探针路由
+func registerHealthRoutes(engine *gin.Engine, conf HealthConfig) {
+	if conf.LivenessPath != "" {
+		engine.GET(conf.LivenessPath, livenessHandler)
+	}
+	if conf.ReadinessPath != "" {
+		engine.GET(conf.ReadinessPath, readinessHandler)
+	}
+}
+
+// livenessHandler 存活探针 进程可响应即视为存活
+func livenessHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readinessHandler 就绪探针 汇总全部已注册的就绪检查 未就绪时返回503
+func readinessHandler(ctx *gin.Context) {
+	r := kernel.CheckReadiness(ctx.Request.Context())
+	status := http.StatusOK
+	if !r.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, r)
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/zjutjh/mygo/foundation/kernel"
 )
 
 // initGinLoggerWriter 初始化gin access logger writer error logger writer
@@ -14,7 +16,7 @@ func initGinLoggerWriter(conf Config) (io.Writer, io.Writer, error) {
 	// access logger
 	var aw io.Writer
 	if conf.Log.AccessFilename == "/dev/stdout" {
-		aw = os.Stdout
+		aw = kernel.Output()
 	} else {
 		_, err := os.OpenFile(conf.Log.AccessFilename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 		if err != nil {
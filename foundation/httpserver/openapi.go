@@ -0,0 +1,64 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/zjutjh/mygo/foundation/command"
+	"github.com/zjutjh/mygo/swagger"
+)
+
+var openapiExportFlags = &struct {
+	Format string `flag:"format" usage:"document format: yaml|json" validate:"oneof=yaml json"`
+	Out    string `flag:"out" short:"o" usage:"output file (default is stdout)"`
+}{Format: "yaml"}
+
+// RegisterOpenAPICommand 注册app openapi命令组
+// export: 通过routeRegister构建路由 (不监听端口) 离线生成与swagger.DocumentHandler一致的OpenAPI文档
+func RegisterOpenAPICommand(routeRegister func(*gin.Engine)) {
+	command.NewGroup("openapi", command.WithShort("OpenAPI文档")).Add("export", func(cmd *cobra.Command, args []string) error {
+		// 仅构建路由 不输出gin的调试信息 文档生成提示输出到标准错误 避免混入文档内容
+		gin.SetMode(gin.ReleaseMode)
+		swagger.SetOutput(os.Stderr)
+		s, err := NewServer(routeRegister)
+		if err != nil {
+			return err
+		}
+		doc := swagger.Document(s.Engine())
+
+		var data []byte
+		switch openapiExportFlags.Format {
+		case "json":
+			data, err = json.MarshalIndent(doc, "", "  ")
+		default:
+			data, err = yaml.Marshal(doc)
+		}
+		if err != nil {
+			return fmt.Errorf("序列化OpenAPI文档错误: %w", err)
+		}
+		if data[len(data)-1] != '\n' {
+			data = append(data, '\n')
+		}
+
+		if openapiExportFlags.Out == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		if err := os.WriteFile(openapiExportFlags.Out, data, 0644); err != nil {
+			return fmt.Errorf("写入OpenAPI文档[%s]错误: %w", openapiExportFlags.Out, err)
+		}
+		return nil
+	},
+		command.WithShort("导出OpenAPI文档"),
+		command.WithLong("通过路由注册函数构建路由 (不监听端口), 以已注册的swagger.CM、业务状态码及鉴权配置离线生成OpenAPI文档, 内容与文档接口输出一致"),
+		command.WithArgs(cobra.NoArgs),
+		command.WithFlags(openapiExportFlags),
+		command.WithQuiet(),
+		command.WithExample("app openapi export\napp openapi export --format json --out openapi.json"),
+	)
+}
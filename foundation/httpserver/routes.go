@@ -0,0 +1,108 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+
+	"github.com/zjutjh/mygo/foundation/command"
+	"github.com/zjutjh/mygo/swagger"
+)
+
+var routesFlags = &struct {
+	Format             string `flag:"format" usage:"output format: table|json" validate:"oneof=table json"`
+	FailOnUndocumented bool   `flag:"fail-on-undocumented" usage:"exit non-zero if any non-builtin route is not registered in swagger.CM"`
+}{Format: "table"}
+
+// RegisterRoutesCommand 注册app routes命令: 通过routeRegister构建路由 (不监听端口)
+// 输出每个路由的方法、路径、中间件链、处理器、是否注册swagger.CM及业务状态码
+func RegisterRoutesCommand(routeRegister func(*gin.Engine)) {
+	command.Add("routes", func(cmd *cobra.Command, args []string) error {
+		// 仅构建路由 不输出gin的调试信息
+		gin.SetMode(gin.ReleaseMode)
+		s, err := NewServer(routeRegister)
+		if err != nil {
+			return err
+		}
+		routes, err := swagger.Routes(s.Engine())
+		if err != nil {
+			return fmt.Errorf("获取路由信息错误: %w", err)
+		}
+
+		switch routesFlags.Format {
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(routes); err != nil {
+				return err
+			}
+		default:
+			if err := printRoutes(cmd.OutOrStdout(), routes); err != nil {
+				return err
+			}
+		}
+
+		if routesFlags.FailOnUndocumented {
+			var undocumented []string
+			for _, r := range routes {
+				if !r.Documented && !r.Builtin {
+					undocumented = append(undocumented, r.Method+" "+r.Path)
+				}
+			}
+			if len(undocumented) > 0 {
+				return fmt.Errorf("发现%d个未注册CM的接口: %s", len(undocumented), strings.Join(undocumented, ", "))
+			}
+		}
+		return nil
+	},
+		command.WithShort("查看HTTP路由"),
+		command.WithLong("通过路由注册函数构建路由 (不监听端口), 输出每个路由的方法、路径、中间件链、处理器、是否注册swagger.CM及业务状态码"),
+		command.WithArgs(cobra.NoArgs),
+		command.WithFlags(routesFlags),
+		command.WithQuiet(),
+		command.WithExample("app routes\napp routes --format json\napp routes --fail-on-undocumented"),
+	)
+}
+
+// printRoutes 以表格输出路由 函数名省略包路径
+func printRoutes(out io.Writer, routes []swagger.RouteInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER\tMIDDLEWARES\tDOC\tCODES")
+	for _, r := range routes {
+		middlewares := make([]string, 0, len(r.Middlewares))
+		for _, m := range r.Middlewares {
+			middlewares = append(middlewares, shortFuncName(m))
+		}
+		doc := "no"
+		switch {
+		case r.Documented:
+			doc = "yes"
+		case r.Builtin:
+			doc = "builtin"
+		}
+		codes := make([]string, 0, len(r.Codes))
+		for _, c := range r.Codes {
+			codes = append(codes, strconv.FormatInt(c.Code, 10))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Method, r.Path, shortFuncName(r.Handler),
+			orDash(strings.Join(middlewares, " -> ")), doc, orDash(strings.Join(codes, ",")))
+	}
+	return w.Flush()
+}
+
+func shortFuncName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"time"
@@ -14,6 +16,8 @@ import (
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
+	"gopkg.in/natefinch/lumberjack.v2"
+
 	"github.com/zjutjh/mygo/config"
 	"github.com/zjutjh/mygo/feishu"
 	"github.com/zjutjh/mygo/foundation/kernel"
@@ -21,6 +25,10 @@ import (
 	"github.com/zjutjh/mygo/kit"
 )
 
+func init() {
+	kernel.RegisterOptionalConfig("http_server", DefaultConfig)
+}
+
 // CommandRegister 启动HTTP Server命令注册
 func CommandRegister(routeRegister func(engine *gin.Engine)) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
@@ -29,8 +37,53 @@ func CommandRegister(routeRegister func(engine *gin.Engine)) func(cmd *cobra.Com
 	}
 }
 
-// StartHTTPServer 启动HTTP Server
+// StartHTTPServer 启动HTTP Server 并阻塞至收到结束信号后优雅关闭
+// 返回 (或启动失败退出进程) 前关闭全部已引导资源
 func StartHTTPServer(routeRegister func(*gin.Engine)) {
+	defer closeResources()
+
+	s, err := NewServer(routeRegister)
+	if err != nil {
+		fmt.Fprintln(kernel.Output(), err)
+		closeResources()
+		os.Exit(1)
+	}
+
+	// 启动http server
+	if err := s.Start(); err != nil {
+		fmt.Fprintln(kernel.Output(), "启动HTTP Server失败:", err)
+		closeResources()
+		os.Exit(1)
+	}
+
+	// 监听等待关闭服务
+	kernel.ListenStop(func() error {
+		if err := s.Shutdown(context.Background()); err != nil {
+			return err
+		}
+		fmt.Fprintln(kernel.Output(), "HTTP Server关闭完成")
+		return nil
+	})
+}
+
+// closeResources 关闭全部已引导资源 (日志最后关闭)
+func closeResources() {
+	if err := kernel.Shutdown(context.Background()); err != nil {
+		fmt.Fprintln(kernel.Output(), "关闭资源错误:", err)
+	}
+}
+
+// Server HTTP Server
+type Server struct {
+	conf   Config
+	engine *gin.Engine
+	server *http.Server
+	ln     net.Listener
+	errs   chan error
+}
+
+// NewServer 按config.yaml[http_server]创建HTTP Server并注册路由 不开始监听
+func NewServer(routeRegister func(*gin.Engine)) (*Server, error) {
 	// 获取配置
 	conf := DefaultConfig
 	config.Pick().UnmarshalKey("http_server", &conf)
@@ -38,30 +91,84 @@ func StartHTTPServer(routeRegister func(*gin.Engine)) {
 	// 初始化gin引擎
 	engine, err := initGinEngine(conf)
 	if err != nil {
-		fmt.Fprintln(os.Stdout, "初始化Gin Engine失败:", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("初始化Gin Engine失败: %w", err)
 	}
 
 	// 注册路由
-	routeRegister(engine)
+	if routeRegister != nil {
+		routeRegister(engine)
+	}
 
 	// 初始化http server
-	server := initHTTPServer(engine, conf)
+	return &Server{
+		conf:   conf,
+		engine: engine,
+		server: initHTTPServer(engine, conf),
+		errs:   make(chan error, 1),
+	}, nil
+}
 
-	// 启动http server
-	go listenHTTPServer(server)
+// Engine 获取gin引擎 可直接用于httptest
+func (s *Server) Engine() *gin.Engine {
+	return s.engine
+}
 
-	// 监听等待关闭服务
-	kernel.ListenStop(func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), conf.ShutdownWaitTimeout)
-		defer cancel()
-		if err := server.Shutdown(ctx); err != nil {
-			return fmt.Errorf("HTTP Server等待优雅处理超时, 错误: %w", err)
-		} else {
-			fmt.Fprintln(os.Stdout, "HTTP Server关闭完成")
+// Addr 获取实际监听地址 未开始监听时为配置的地址
+func (s *Server) Addr() string {
+	if s.ln != nil {
+		return s.ln.Addr().String()
+	}
+	return s.conf.Addr
+}
+
+// Start 开始监听并在后台处理请求 监听失败时返回错误
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.conf.Addr)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintln(kernel.Output(), "HTTP Server运行错误:", err)
+			s.errs <- err
 		}
-		return nil
-	})
+	}()
+	return nil
+}
+
+// Err 返回HTTP Server运行中发生的错误 (非正常关闭)
+func (s *Server) Err() <-chan error {
+	return s.errs
+}
+
+// Run 启动HTTP Server并阻塞 ctx取消时优雅关闭并返回nil 运行中发生错误时返回该错误
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.Start(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return s.Shutdown(context.Background())
+	case err := <-s.errs:
+		return err
+	}
+}
+
+// Shutdown 优雅关闭HTTP Server: 先使就绪探针失败 再等待存量请求处理完成
+// 等待时长受ctx与配置的shutdown_wait_timeout共同约束
+func (s *Server) Shutdown(ctx context.Context) error {
+	kernel.MarkShuttingDown()
+	if s.conf.Health.ShutdownDelay > 0 {
+		time.Sleep(s.conf.Health.ShutdownDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.conf.ShutdownWaitTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("HTTP Server等待优雅处理超时, 错误: %w", err)
+	}
+	return nil
 }
 
 func initGinEngine(conf Config) (*gin.Engine, error) {
@@ -69,6 +176,14 @@ func initGinEngine(conf Config) (*gin.Engine, error) {
 	if err != nil {
 		return nil, err
 	}
+	// 日志文件在其他资源关闭后最后关闭
+	kernel.OnCloseLast("httpserver:log", func(context.Context) error {
+		return closeLogWriters(aw, ew)
+	})
+	// 收到SIGHUP时关闭日志文件句柄 下次写入时重新打开
+	kernel.OnSignal(kernel.SignalReload, "httpserver:log", func() error {
+		return closeLogWriters(aw, ew)
+	})
 
 	// 创建gin引擎实例
 	engine := gin.New()
@@ -86,6 +201,11 @@ func initGinEngine(conf Config) (*gin.Engine, error) {
 	// 设置gin崩溃恢复中间件
 	recovery := gin.RecoveryWithWriter(ew, recoveryHandler)
 
+	// 注册探针路由 (先于全局中间件注册 不记录访问日志)
+	if conf.Health.Enable {
+		registerHealthRoutes(engine, conf.Health)
+	}
+
 	// 设置gin全局中间件
 	engine.Use(requestid.New(), logger, recovery)
 
@@ -138,16 +258,20 @@ func recoveryHandler(ctx *gin.Context, err any) {
 	}()
 }
 
+// closeLogWriters 关闭日志文件句柄
+func closeLogWriters(ws ...io.Writer) error {
+	var errs []error
+	for _, w := range ws {
+		if l, ok := w.(*lumberjack.Logger); ok {
+			errs = append(errs, l.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func initHTTPServer(e *gin.Engine, conf Config) *http.Server {
 	return &http.Server{
 		Addr:    conf.Addr,
 		Handler: e.Handler(),
 	}
 }
-
-func listenHTTPServer(s *http.Server) {
-	err := s.ListenAndServe()
-	if err != nil && !errors.Is(err, http.ErrServerClosed) {
-		fmt.Fprintln(os.Stdout, "启动HTTP Server失败:", err)
-	}
-}
@@ -1,6 +1,7 @@
 package kernel
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -8,36 +9,91 @@ import (
 	"github.com/zjutjh/mygo/config"
 )
 
-type BootList []func() error
+var (
+	// ErrConfig 配置加载或校验阶段的错误 可通过errors.Is判断
+	ErrConfig = errors.New("配置错误")
+	// ErrBoot 资源引导阶段的错误 可通过errors.Is判断
+	ErrBoot = errors.New("引导错误")
+)
+
+// stageError 标记错误所属的引导阶段 不改变错误信息
+type stageError struct {
+	stage error
+	err   error
+}
+
+func (e *stageError) Error() string   { return e.err.Error() }
+func (e *stageError) Unwrap() []error { return []error{e.stage, e.err} }
 
+// BootList 引导步骤列表 元素为Step或旧式func() error引导函数
+// Step的执行顺序由步骤间的依赖关系决定 与列表顺序无关; func() error经Func适配 在列表中位于其之前的全部步骤之后执行
+type BootList []any
+
+// Bootstrap 引导应用 任一环节失败时输出错误并退出进程
 func Bootstrap(confPath string, bootRegister func() BootList) {
+	if err := Boot(context.Background(), confPath, bootRegister); err != nil {
+		fmt.Fprintln(output, err)
+		os.Exit(1)
+	}
+}
+
+// Boot 引导应用: 加载配置目录、校验配置、按依赖引导资源并按需监听配置变更
+// 失败时返回错误 (可通过errors.Is判断ErrConfig/ErrBoot) 已引导的资源会被关闭
+func Boot(ctx context.Context, confPath string, bootRegister func() BootList) error {
 	// 加载配置
 	if err := config.Boot(confPath); err != nil {
-		fmt.Fprintln(os.Stdout, "引导加载配置错误:", err)
-		os.Exit(1)
+		return &stageError{ErrConfig, fmt.Errorf("引导加载配置错误: %w", err)}
 	}
 
-	// 检查配置
-	if err := checkConfig(); err != nil {
-		fmt.Fprintln(os.Stdout, "引导配置发现错误:", err)
-		os.Exit(1)
+	if err := BootResources(ctx, bootRegister); err != nil {
+		return err
 	}
 
-	// 引导与加载资源
-	bs := bootRegister()
-	for _, boot := range bs {
-		if err := boot(); err != nil {
-			fmt.Fprintln(os.Stdout, "引导加载资源错误:", err)
-			os.Exit(1)
+	// 监听配置变更
+	if config.AppWatch() {
+		if err := config.Watch(); err != nil {
+			shutdown()
+			return &stageError{ErrBoot, fmt.Errorf("引导监听配置错误: %w", err)}
 		}
 	}
+	return nil
 }
 
-func checkConfig() error {
+// BootResources 在配置已加载的前提下校验配置并按依赖引导资源
+// 引导受ctx与BootTimeout共同约束 失败时返回错误 (可通过errors.Is判断ErrConfig/ErrBoot) 已引导的资源会被关闭
+func BootResources(ctx context.Context, bootRegister func() BootList) error {
+	// 注册引导器 (同时注册各资源的配置结构)
+	var bs BootList
+	if bootRegister != nil {
+		bs = bootRegister()
+	}
+
+	// 检查配置
+	if err := CheckConfig(); err != nil {
+		return &stageError{ErrConfig, fmt.Errorf("引导配置发现错误: %w", err)}
+	}
+
+	// 按依赖关系引导与加载资源
+	if err := runSteps(ctx, bs); err != nil {
+		// 关闭已引导的资源
+		shutdown()
+		return &stageError{ErrBoot, fmt.Errorf("引导加载资源错误: %w", err)}
+	}
+	return nil
+}
+
+// CheckConfig 检查配置 包括应用基础配置与已注册的配置结构
+func CheckConfig() error {
 	// 检测app.yaml是否正确
 	config.Pick()
 	if config.AppName() == "" {
-		return errors.New("未配置应用Name, 请在app.yaml[app.name]中配置")
+		return fmt.Errorf("%w: 未配置应用Name, 请在app.yaml[app.name]中配置", ErrConfig)
 	}
-	return nil
+	// 校验已注册的配置结构
+	return ValidateConfig()
+}
+
+func init() {
+	// app配置段常被项目追加自定义配置项 仅校验框架声明的字段
+	registerLenientConfig("app", config.AppConfig{})
 }
@@ -0,0 +1,119 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HealthCheckTimeout 单个就绪检查的超时时间
+var HealthCheckTimeout = 3 * time.Second
+
+// HealthCheck 就绪检查 返回nil表示就绪
+type HealthCheck func(ctx context.Context) error
+
+// CheckResult 单个就绪检查结果
+type CheckResult struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Readiness 就绪检查汇总结果
+type Readiness struct {
+	Ready  bool          `json:"ready"`
+	Reason string        `json:"reason,omitempty"`
+	Checks []CheckResult `json:"checks"`
+}
+
+var (
+	healthChecks   = map[string]HealthCheck{}
+	healthChecksMu sync.RWMutex
+	// shuttingDown 服务进入关闭流程后就绪检查一律失败
+	shuttingDown atomic.Bool
+)
+
+// RegisterHealthCheck 注册就绪检查 同名检查会被替换
+func RegisterHealthCheck(name string, check HealthCheck) {
+	healthChecksMu.Lock()
+	defer healthChecksMu.Unlock()
+	healthChecks[name] = check
+}
+
+// MarkShuttingDown 标记服务进入关闭流程 此后就绪检查一律失败 使流量在服务停止前被摘除
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// CheckReadiness 并行执行全部就绪检查 结果按检查名排序
+func CheckReadiness(ctx context.Context) Readiness {
+	healthChecksMu.RLock()
+	names := make([]string, 0, len(healthChecks))
+	for name := range healthChecks {
+		names = append(names, name)
+	}
+	checks := make(map[string]HealthCheck, len(healthChecks))
+	for name, check := range healthChecks {
+		checks[name] = check
+	}
+	healthChecksMu.RUnlock()
+	sort.Strings(names)
+
+	r := Readiness{Ready: true, Checks: make([]CheckResult, len(names))}
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Checks[i] = runHealthCheck(ctx, name, checks[name])
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range r.Checks {
+		if !c.OK {
+			r.Ready = false
+			r.Reason = "就绪检查未通过"
+		}
+	}
+	if shuttingDown.Load() {
+		r.Ready = false
+		r.Reason = "服务正在关闭"
+	}
+	return r
+}
+
+// runHealthCheck 执行单个就绪检查
+func runHealthCheck(ctx context.Context, name string, check HealthCheck) (res CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	res.Name = name
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("检查超时: %w", ctx.Err())
+	}
+	res.Latency = time.Since(start).String()
+	res.OK = err == nil
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
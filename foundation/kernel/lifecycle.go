@@ -0,0 +1,95 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CloseHookTimeout 单个资源关闭钩子的超时时间
+var CloseHookTimeout = 5 * time.Second
+
+// CloseHook 资源关闭钩子
+type CloseHook func(ctx context.Context) error
+
+type closer struct {
+	name string
+	hook CloseHook
+}
+
+var (
+	closers     []closer
+	lastClosers []closer
+	closersMu   sync.Mutex
+)
+
+// OnClose 注册资源关闭钩子 一般在资源Boot成功后注册
+// Shutdown时按注册的逆序执行 即后引导的资源先关闭
+func OnClose(name string, hook CloseHook) {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+	closers = append(closers, closer{name: name, hook: hook})
+}
+
+// OnCloseLast 注册最后执行的关闭钩子 用于日志等需在其他资源关闭后再flush的资源
+// 在全部OnClose钩子执行完成后按注册的逆序执行
+func OnCloseLast(name string, hook CloseHook) {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+	lastClosers = append(lastClosers, closer{name: name, hook: hook})
+}
+
+// Shutdown 关闭全部已注册资源 每个钩子最多等待CloseHookTimeout (同时受ctx约束)
+// 钩子执行后即被移除 重复调用不会重复关闭 返回全部关闭错误
+func Shutdown(ctx context.Context) error {
+	closersMu.Lock()
+	list := make([]closer, 0, len(closers)+len(lastClosers))
+	for i := len(closers) - 1; i >= 0; i-- {
+		list = append(list, closers[i])
+	}
+	for i := len(lastClosers) - 1; i >= 0; i-- {
+		list = append(list, lastClosers[i])
+	}
+	closers, lastClosers = nil, nil
+	closersMu.Unlock()
+
+	var errs []error
+	for _, c := range list {
+		if err := runCloseHook(ctx, c.hook); err != nil {
+			errs = append(errs, fmt.Errorf("关闭资源[%s]错误: %w", c.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runCloseHook 执行单个关闭钩子 超时后不再等待
+func runCloseHook(ctx context.Context, hook CloseHook) error {
+	ctx, cancel := context.WithTimeout(ctx, CloseHookTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- hook(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("等待关闭超时: %w", ctx.Err())
+	}
+}
+
+// shutdown 关闭全部已注册资源并输出错误
+func shutdown() {
+	if err := Shutdown(context.Background()); err != nil {
+		fmt.Fprintln(output, "关闭资源错误:", err)
+	}
+}
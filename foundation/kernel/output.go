@@ -0,0 +1,23 @@
+package kernel
+
+import (
+	"io"
+	"os"
+
+	"github.com/zjutjh/mygo/config"
+)
+
+// output 框架提示信息的输出位置
+var output io.Writer = os.Stdout
+
+// SetOutput 设置框架提示信息 (引导、关闭、信号与配置变更处理等) 的输出位置 默认为标准输出
+// 同时作用于config包 需在引导前设置
+func SetOutput(w io.Writer) {
+	output = w
+	config.SetOutput(w)
+}
+
+// Output 获取框架提示信息的输出位置 供各组件输出运行提示
+func Output() io.Writer {
+	return output
+}
@@ -0,0 +1,104 @@
+package kernel
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/samber/do"
+
+	"github.com/zjutjh/mygo/config"
+)
+
+// resetter 组件级全局状态的重置处理
+type resetter struct {
+	name  string
+	reset func() (restore func())
+}
+
+var (
+	resetters   []resetter
+	resettersMu sync.Mutex
+)
+
+// OnReset 注册组件级全局状态 (如任务、迁移注册表) 的重置处理 同名处理会被替换 一般在组件包init中注册
+// Reset时调用reset切换到初始状态 恢复原状态时调用其返回的restore
+func OnReset(name string, reset func() (restore func())) {
+	resettersMu.Lock()
+	defer resettersMu.Unlock()
+	for i, r := range resetters {
+		if r.name == name {
+			resetters[i].reset = reset
+			return
+		}
+	}
+	resetters = append(resetters, resetter{name: name, reset: reset})
+}
+
+// Reset 保存并重置进程级全局状态: do默认容器、配置状态、资源关闭钩子、就绪检查与运行时信号处理注册表
+// 以及通过OnReset注册的组件状态 (定时任务注册表与执行历史、迁移注册表等)
+// 已注册的配置结构 (含init中注册的) 会保留 重置后新注册的不会泄漏到原状态
+// 返回的restore用于恢复原状态 恢复前应先调用Shutdown关闭重置后引导的资源
+// Reset直接替换全局状态 不提供并发隔离: 同一时刻仅允许一个重置状态处于活动中 不可用于并行测试 (如t.Parallel)
+// 调用Reset与restore时不应有仍在访问资源的后台goroutine
+func Reset() (restore func()) {
+	prevInjector := do.DefaultInjector
+	do.DefaultInjector = do.New()
+	restoreConfig := config.Reset()
+
+	schemaMu.Lock()
+	prevSchemas := schemas
+	schemas = make(map[string]schema, len(prevSchemas))
+	for k, s := range prevSchemas {
+		schemas[k] = s
+	}
+	schemaMu.Unlock()
+
+	closersMu.Lock()
+	prevClosers, prevLastClosers := closers, lastClosers
+	closers, lastClosers = nil, nil
+	closersMu.Unlock()
+
+	healthChecksMu.Lock()
+	prevHealthChecks := healthChecks
+	healthChecks = map[string]HealthCheck{}
+	healthChecksMu.Unlock()
+	prevShuttingDown := shuttingDown.Swap(false)
+
+	signalHandlersMu.Lock()
+	prevSignalHandlers := signalHandlers
+	signalHandlers = map[RuntimeSignal][]signalHandler{}
+	signalHandlersMu.Unlock()
+
+	resettersMu.Lock()
+	restores := make([]func(), 0, len(resetters))
+	for _, r := range resetters {
+		restores = append(restores, r.reset())
+	}
+	resettersMu.Unlock()
+
+	return func() {
+		for _, r := range slices.Backward(restores) {
+			r()
+		}
+
+		restoreConfig()
+		do.DefaultInjector = prevInjector
+
+		schemaMu.Lock()
+		schemas = prevSchemas
+		schemaMu.Unlock()
+
+		closersMu.Lock()
+		closers, lastClosers = prevClosers, prevLastClosers
+		closersMu.Unlock()
+
+		healthChecksMu.Lock()
+		healthChecks = prevHealthChecks
+		healthChecksMu.Unlock()
+		shuttingDown.Store(prevShuttingDown)
+
+		signalHandlersMu.Lock()
+		signalHandlers = prevSignalHandlers
+		signalHandlersMu.Unlock()
+	}
+}
@@ -0,0 +1,244 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jinzhu/copier"
+
+	"github.com/zjutjh/mygo/config"
+)
+
+// schema 配置结构描述
+type schema struct {
+	key      string
+	conf     any
+	optional bool
+	lenient  bool // 不检查未知配置项 仅校验已声明字段
+}
+
+var (
+	schemas  = map[string]schema{}
+	schemaMu sync.Mutex
+
+	validate = newValidator()
+)
+
+// RegisterConfig 注册config.yaml[key]对应的配置结构 引导时对其进行严格校验
+// conf 通常为provider的DefaultConfig, 校验时在其副本上合并配置内容
+// 支持通过validate tag声明必填与取值范围约束
+func RegisterConfig(key string, conf any) {
+	registerSchema(schema{key: key, conf: conf})
+}
+
+// RegisterOptionalConfig 注册可选的配置结构 config.yaml[key]不存在时跳过校验
+func RegisterOptionalConfig(key string, conf any) {
+	registerSchema(schema{key: key, conf: conf, optional: true})
+}
+
+// registerLenientConfig 注册仅校验已声明字段的配置结构
+// 用于框架自身的公共配置段 (如app) 项目可在其中追加自定义配置项
+func registerLenientConfig(key string, conf any) {
+	registerSchema(schema{key: key, conf: conf, lenient: true})
+}
+
+func registerSchema(s schema) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	// 同一配置键既有必需注册又有可选注册时 以必需为准
+	if old, ok := schemas[s.key]; ok && !old.optional {
+		s.optional = false
+	}
+	schemas[s.key] = s
+}
+
+// ConfigError 配置校验汇总错误
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("配置校验发现%d处错误:\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+// ValidateConfig 严格校验全部已注册的配置结构: 未知配置项、类型错误、必填项与取值范围
+// 全部配置校验完成后汇总返回 而非在首个错误处中止
+func ValidateConfig() error {
+	schemaMu.Lock()
+	list := make([]schema, 0, len(schemas))
+	for _, s := range schemas {
+		list = append(list, s)
+	}
+	schemaMu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].key < list[j].key
+	})
+
+	var problems []string
+	for _, s := range list {
+		problems = append(problems, validateSchema(s)...)
+	}
+	if len(problems) != 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// validateSchema 校验单个配置结构
+func validateSchema(s schema) []string {
+	cfg := config.Pick()
+	if !cfg.IsSet(s.key) {
+		if s.optional {
+			return nil
+		}
+		return []string{fmt.Sprintf("config.yaml[%s]: 配置不存在", s.key)}
+	}
+
+	// 在默认配置副本上解析配置
+	t := reflect.TypeOf(s.conf)
+	ptr := reflect.New(t)
+	if err := copier.CopyWithOption(ptr.Interface(), s.conf, copier.Option{DeepCopy: true}); err != nil {
+		return []string{fmt.Sprintf("config.yaml[%s]: 复制默认配置错误: %s", s.key, err)}
+	}
+	err := cfg.UnmarshalKey(s.key, ptr.Interface())
+
+	var problems []string
+	// 未知配置项
+	if !s.lenient {
+		for _, k := range unknownKeys(cfg.Get(s.key), t, s.key) {
+			problems = append(problems, fmt.Sprintf("config.yaml[%s]: 未知配置项", k))
+		}
+	}
+	// 类型错误
+	for _, e := range flattenErrors(err) {
+		problems = append(problems, fmt.Sprintf("config.yaml[%s]: %s", s.key, e))
+	}
+	if t.Kind() != reflect.Struct {
+		return problems
+	}
+
+	// 必填项与取值范围
+	var ves validator.ValidationErrors
+	if err := validate.Struct(ptr.Interface()); errors.As(err, &ves) {
+		for _, fe := range ves {
+			field := fe.Namespace()[strings.Index(fe.Namespace(), ".")+1:]
+			problems = append(problems, fmt.Sprintf("config.yaml[%s.%s]: 值[%v]不满足约束[%s]", s.key, field, fe.Value(), constraint(fe)))
+		}
+	} else if err != nil {
+		problems = append(problems, fmt.Sprintf("config.yaml[%s]: %s", s.key, err))
+	}
+	return problems
+}
+
+// newValidator 创建以mapstructure tag命名字段的校验器
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+func constraint(fe validator.FieldError) string {
+	if fe.Param() == "" {
+		return fe.Tag()
+	}
+	return fe.Tag() + "=" + fe.Param()
+}
+
+// unknownKeys 对照配置结构的mapstructure tag 找出配置中不存在于结构的键
+func unknownKeys(raw any, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var keys []string
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := structFields(t)
+		for k, v := range m {
+			ft, ok := fields[strings.ToLower(k)]
+			if !ok {
+				keys = append(keys, prefix+"."+k)
+				continue
+			}
+			keys = append(keys, unknownKeys(v, ft, prefix+"."+k)...)
+		}
+	case reflect.Map:
+		m, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for k, v := range m {
+			keys = append(keys, unknownKeys(v, t.Elem(), prefix+"."+k)...)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]any)
+		if !ok {
+			return nil
+		}
+		for i, v := range items {
+			keys = append(keys, unknownKeys(v, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// structFields 获取结构体mapstructure字段名 (小写) 到字段类型的映射 匿名或squash字段平铺
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && (strings.Contains(opts, "squash") || (f.Anonymous && name == "")) {
+			for k, v := range structFields(ft) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
+
+// flattenErrors 展开errors.Join聚合的错误
+func flattenErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		var msgs []string
+		for _, e := range joined.Unwrap() {
+			msgs = append(msgs, flattenErrors(e)...)
+		}
+		return msgs
+	}
+	return []string{err.Error()}
+}
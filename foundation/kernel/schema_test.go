@@ -0,0 +1,49 @@
+package kernel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zjutjh/mygo/config"
+)
+
+type testConf struct {
+	Addr    string `mapstructure:"addr" validate:"required"`
+	MaxConn int    `mapstructure:"max_conn" validate:"gt=0"`
+}
+
+func TestValidateSchema(t *testing.T) {
+	defer Reset()()
+	err := config.BootMap(map[string]map[string]any{
+		"config": {
+			"app":    map[string]any{"name": "test", "env": "prod", "custom": "x"},
+			"strict": map[string]any{"addr": "127.0.0.1", "max_conn": 1, "max_con": 2},
+			"bad":    map[string]any{"max_conn": "many"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		schema schema
+		want   []string
+	}{
+		{"框架app段忽略自定义配置项", schema{key: "app", conf: config.AppConfig{}, lenient: true}, nil},
+		{"严格校验未知配置项", schema{key: "strict", conf: testConf{}}, []string{"config.yaml[strict.max_con]: 未知配置项"}},
+		{"宽松模式仍校验已声明字段", schema{key: "bad", conf: testConf{Addr: "default"}, lenient: true}, []string{
+			"config.yaml[bad]: 'max_conn' cannot parse value as 'int': strconv.ParseInt: invalid syntax",
+			"config.yaml[bad.max_conn]: 值[0]不满足约束[gt=0]",
+		}},
+		{"缺失必需配置", schema{key: "missing", conf: testConf{}}, []string{"config.yaml[missing]: 配置不存在"}},
+		{"缺失可选配置", schema{key: "missing", conf: testConf{}, optional: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateSchema(tt.schema); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("validateSchema() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
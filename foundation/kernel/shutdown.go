@@ -8,13 +8,17 @@ import (
 )
 
 // ListenStop 监听结束信号并注册处理逻辑器
+// 等待期间同时处理运行时运维信号 (见ListenRuntimeSignals)
 func ListenStop(handler func() error) {
+	stop := ListenRuntimeSignals()
+	defer stop()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	err := handler()
 	if err != nil {
-		fmt.Fprintln(os.Stdout, "关闭处理错误:", err)
+		fmt.Fprintln(output, "关闭处理错误:", err)
 		// os.Exit(1)
 	}
 }
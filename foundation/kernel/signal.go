@@ -0,0 +1,120 @@
+package kernel
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/zjutjh/mygo/config"
+)
+
+// RuntimeSignal 运行时运维信号 (仅非windows平台可用)
+type RuntimeSignal int
+
+const (
+	// SignalReload SIGHUP 重新加载配置并重新打开日志文件 (配合logrotate使用)
+	SignalReload RuntimeSignal = iota
+	// SignalDiagnose SIGUSR1 输出全部goroutine堆栈与运行时统计
+	SignalDiagnose
+	// SignalToggleDebug SIGUSR2 切换debug日志级别
+	SignalToggleDebug
+)
+
+func (s RuntimeSignal) String() string {
+	switch s {
+	case SignalReload:
+		return "SIGHUP"
+	case SignalDiagnose:
+		return "SIGUSR1"
+	case SignalToggleDebug:
+		return "SIGUSR2"
+	}
+	return fmt.Sprintf("RuntimeSignal(%d)", int(s))
+}
+
+type signalHandler struct {
+	name string
+	fn   func() error
+}
+
+var (
+	signalHandlers   = map[RuntimeSignal][]signalHandler{}
+	signalHandlersMu sync.Mutex
+
+	startTime = time.Now()
+)
+
+// OnSignal 注册运行时信号处理 同一信号下同名处理会被替换 按注册顺序执行
+func OnSignal(sig RuntimeSignal, name string, fn func() error) {
+	signalHandlersMu.Lock()
+	defer signalHandlersMu.Unlock()
+	for i, h := range signalHandlers[sig] {
+		if h.name == name {
+			signalHandlers[sig][i].fn = fn
+			return
+		}
+	}
+	signalHandlers[sig] = append(signalHandlers[sig], signalHandler{name: name, fn: fn})
+}
+
+// handleSignal 处理运行时信号
+// SIGHUP先重新加载配置再执行处理 SIGUSR1未注册处理时输出到标准输出
+func handleSignal(sig RuntimeSignal) {
+	fmt.Fprintf(output, "收到运行时信号[%s]\n", sig)
+	if sig == SignalReload {
+		if err := config.Reload(); err != nil {
+			fmt.Fprintln(output, "重新加载配置错误:", err)
+		}
+	}
+
+	signalHandlersMu.Lock()
+	handlers := append([]signalHandler(nil), signalHandlers[sig]...)
+	signalHandlersMu.Unlock()
+
+	if sig == SignalDiagnose && len(handlers) == 0 {
+		fmt.Fprintf(output, "运行时统计: %v\n%s", RuntimeStats(), GoroutineStacks())
+		return
+	}
+	for _, h := range handlers {
+		if err := callSignalHandler(h.fn); err != nil {
+			fmt.Fprintf(output, "处理运行时信号[%s][%s]错误: %s\n", sig, h.name, err)
+		}
+	}
+}
+
+func callSignalHandler(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// RuntimeStats 运行时统计
+func RuntimeStats() map[string]any {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return map[string]any{
+		"uptime":         time.Since(startTime).Round(time.Second).String(),
+		"goroutines":     runtime.NumGoroutine(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"num_cpu":        runtime.NumCPU(),
+		"heap_alloc":     ms.HeapAlloc,
+		"heap_inuse":     ms.HeapInuse,
+		"heap_objects":   ms.HeapObjects,
+		"sys":            ms.Sys,
+		"num_gc":         ms.NumGC,
+		"gc_pause_total": time.Duration(ms.PauseTotalNs).String(),
+	}
+}
+
+// GoroutineStacks 全部goroutine堆栈
+func GoroutineStacks() string {
+	var buf bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&buf, 2)
+	return buf.String()
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package kernel
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var runtimeSignals = map[os.Signal]RuntimeSignal{
+	syscall.SIGHUP:  SignalReload,
+	syscall.SIGUSR1: SignalDiagnose,
+	syscall.SIGUSR2: SignalToggleDebug,
+}
+
+// ListenRuntimeSignals 开始处理SIGHUP/SIGUSR1/SIGUSR2运行时信号 返回的stop用于停止处理
+func ListenRuntimeSignals() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				handleSignal(runtimeSignals[sig])
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package kernel
+
+// ListenRuntimeSignals windows不支持SIGHUP/SIGUSR1/SIGUSR2 不做处理
+func ListenRuntimeSignals() (stop func()) {
+	return func() {}
+}
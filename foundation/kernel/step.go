@@ -0,0 +1,268 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BootTimeout 引导全部资源的超时时间
+var BootTimeout = time.Minute
+
+// Step 命名引导步骤
+type Step struct {
+	// Name 步骤名 供其他步骤声明依赖 不可重复
+	Name string
+	// Needs 必需依赖 依赖的步骤未加入BootList时报错
+	Needs []string
+	// After 可选依赖 仅当依赖的步骤已加入BootList时在其之后执行
+	After []string
+	// Run 引导逻辑
+	Run func() error
+
+	// ordered 在BootList中位于其之前的全部步骤之后执行 (Func)
+	ordered bool
+}
+
+// NewStep 创建引导步骤 needs为必需依赖的步骤名
+func NewStep(name string, run func() error, needs ...string) Step {
+	return Step{Name: name, Needs: needs, Run: run}
+}
+
+// Func 将旧式func() error引导函数适配为引导步骤
+// 与旧式BootList一致 在BootList中位于其之前的全部步骤之后执行
+func Func(name string, run func() error) Step {
+	return Step{Name: name, Run: run, ordered: true}
+}
+
+// DependsOn 追加必需依赖
+func (s Step) DependsOn(names ...string) Step {
+	s.Needs = append(slices.Clone(s.Needs), names...)
+	return s
+}
+
+// RunsAfter 追加可选依赖
+func (s Step) RunsAfter(names ...string) Step {
+	s.After = append(slices.Clone(s.After), names...)
+	return s
+}
+
+// plan 校验引导步骤 返回各步骤实际依赖的步骤名
+func plan(steps []Step) (map[string][]string, error) {
+	names := map[string]bool{}
+	for _, s := range steps {
+		if s.Name == "" {
+			return nil, errors.New("引导步骤未命名")
+		}
+		if s.Run == nil {
+			return nil, fmt.Errorf("引导步骤[%s]未设置Run", s.Name)
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("引导步骤[%s]重复注册", s.Name)
+		}
+		names[s.Name] = true
+	}
+
+	deps := make(map[string][]string, len(steps))
+	var missing []string
+	for _, s := range steps {
+		for _, n := range s.Needs {
+			if !names[n] {
+				missing = append(missing, fmt.Sprintf("引导步骤[%s]依赖[%s], 但BootList中未注册该步骤", s.Name, n))
+				continue
+			}
+			if !slices.Contains(deps[s.Name], n) {
+				deps[s.Name] = append(deps[s.Name], n)
+			}
+		}
+		for _, n := range s.After {
+			if names[n] && !slices.Contains(deps[s.Name], n) {
+				deps[s.Name] = append(deps[s.Name], n)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.New(strings.Join(missing, "\n"))
+	}
+
+	// 检测循环依赖
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			i := slices.Index(path, name)
+			return fmt.Errorf("引导步骤存在循环依赖: %s -> %s", strings.Join(path[i:], " -> "), name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, d := range deps[name] {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, s := range steps {
+		if err := visit(s.Name); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// steps 将BootList规范化为引导步骤 func() error经Func适配 以其在列表中的位置与函数名命名
+func (bs BootList) steps() ([]Step, error) {
+	steps := make([]Step, 0, len(bs))
+	for i, b := range bs {
+		switch b := b.(type) {
+		case Step:
+			steps = append(steps, b)
+		case func() error:
+			steps = append(steps, Func(fmt.Sprintf("#%d(%s)", i, funcName(b)), b))
+		default:
+			return nil, fmt.Errorf("BootList[%d]: 不支持的引导步骤类型%T, 应为kernel.Step或func() error", i, b)
+		}
+	}
+	return steps, nil
+}
+
+// funcName 获取函数名 (去除包路径)
+func funcName(fn func() error) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// stepResult 引导步骤执行结果
+type stepResult struct {
+	name string
+	err  error
+}
+
+// runSteps 按依赖关系执行引导步骤 相互独立的步骤并行执行 整体受ctx与BootTimeout约束
+// 任一步骤失败后不再启动新的步骤 等待执行中的步骤结束后返回全部错误
+// 超时或被取消时立即返回 (可通过errors.Is判断context.DeadlineExceeded) 执行中的步骤结束后在后台关闭其引导的资源
+func runSteps(ctx context.Context, bs BootList) error {
+	steps, err := bs.steps()
+	if err != nil {
+		return err
+	}
+	for i, s := range steps {
+		if s.ordered {
+			for _, prev := range steps[:i] {
+				steps[i] = steps[i].RunsAfter(prev.Name)
+			}
+		}
+	}
+	deps, err := plan(steps)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]Step, len(steps))
+	pending := make(map[string]int, len(steps))
+	dependents := map[string][]string{}
+	for _, s := range steps {
+		byName[s.Name] = s
+		pending[s.Name] = len(deps[s.Name])
+		for _, d := range deps[s.Name] {
+			dependents[d] = append(dependents[d], s.Name)
+		}
+	}
+
+	results := make(chan stepResult, len(steps))
+	started := map[string]bool{}
+	running := map[string]bool{}
+	start := func(name string) {
+		started[name], running[name] = true, true
+		go func() {
+			results <- stepResult{name: name, err: runStep(byName[name])}
+		}()
+	}
+	for _, s := range steps {
+		if pending[s.Name] == 0 {
+			start(s.Name)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, BootTimeout)
+	defer cancel()
+	var errs []error
+	for len(running) > 0 {
+		select {
+		case r := <-results:
+			delete(running, r.name)
+			if r.err != nil {
+				errs = append(errs, r.err)
+				continue
+			}
+			if len(errs) > 0 {
+				continue
+			}
+			for _, n := range dependents[r.name] {
+				pending[n]--
+				if pending[n] == 0 {
+					start(n)
+				}
+			}
+		case <-ctx.Done():
+			// 步骤无法中断 不再等待执行中的步骤
+			go closeLate(results, len(running))
+			var runningNames, waiting []string
+			for _, s := range steps {
+				if running[s.Name] {
+					runningNames = append(runningNames, s.Name)
+				} else if !started[s.Name] {
+					waiting = append(waiting, s.Name)
+				}
+			}
+			err := fmt.Errorf("引导超时或被取消(%w), 执行中的步骤: %s", ctx.Err(), strings.Join(runningNames, ", "))
+			if len(waiting) > 0 {
+				err = fmt.Errorf("%w; 未启动的步骤: %s", err, strings.Join(waiting, ", "))
+			}
+			return errors.Join(append(errs, err)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeLate 等待超时后仍在执行的n个步骤 每个步骤结束后关闭其注册的资源
+// 超时返回时已引导资源已被关闭 此后注册的关闭钩子均来自这些步骤
+func closeLate(results <-chan stepResult, n int) {
+	for range n {
+		r := <-results
+		if r.err != nil {
+			fmt.Fprintf(output, "引导步骤[%s]超时后结束: %s\n", r.name, r.err)
+		}
+		if err := Shutdown(context.Background()); err != nil {
+			fmt.Fprintf(output, "关闭引导步骤[%s]的资源错误: %s\n", r.name, err)
+		}
+	}
+}
+
+// runStep 执行单个引导步骤
+func runStep(s Step) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("引导步骤[%s]发生panic: %v", s.Name, r)
+		}
+	}()
+	if err := s.Run(); err != nil {
+		return fmt.Errorf("引导步骤[%s]错误: %w", s.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunStepsOrder(t *testing.T) {
+	var order []string
+	step := func(name string) func() error {
+		return func() error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	bs := BootList{
+		NewStep("redis", step("redis"), "log"),
+		step("legacy"),
+		NewStep("log", step("log")),
+	}
+	if err := runSteps(context.Background(), bs); err != nil {
+		t.Fatal(err)
+	}
+	// func() error 在列表中位于其之前的全部步骤之后执行
+	if got := strings.Join(order, ","); got != "log,redis,legacy" {
+		t.Fatalf("order = %s, want log,redis,legacy", got)
+	}
+}
+
+func TestRunStepsInvalid(t *testing.T) {
+	nop := func() error { return nil }
+	tests := []struct {
+		name string
+		bs   BootList
+		want string
+	}{
+		{"不支持的类型", BootList{func() {}}, "BootList[0]: 不支持的引导步骤类型func()"},
+		{"缺失依赖", BootList{NewStep("lock", nop, "redis")}, "引导步骤[lock]依赖[redis], 但BootList中未注册该步骤"},
+		{"循环依赖", BootList{NewStep("a", nop, "b"), NewStep("b", nop, "a")}, "引导步骤存在循环依赖: a -> b -> a"},
+		{"重复注册", BootList{NewStep("a", nop), NewStep("a", nop)}, "引导步骤[a]重复注册"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runSteps(context.Background(), tt.bs)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("runSteps() = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStepsTimeout(t *testing.T) {
+	defer Reset()()
+	release := make(chan struct{})
+	closed := make(chan struct{})
+	bs := BootList{
+		NewStep("slow", func() error {
+			<-release
+			OnClose("slow", func(ctx context.Context) error {
+				close(closed)
+				return nil
+			})
+			return nil
+		}),
+		NewStep("next", func() error { return nil }, "slow"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := runSteps(ctx, bs)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("runSteps() = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "执行中的步骤: slow; 未启动的步骤: next") {
+		t.Fatalf("runSteps() = %v, want pending step names", err)
+	}
+
+	// 超时后结束的步骤在后台关闭其资源
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("late step resources were not closed")
+	}
+}
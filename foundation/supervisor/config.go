@@ -0,0 +1,15 @@
+package supervisor
+
+import "time"
+
+var DefaultConfig = Config{
+	ShutdownWaitTimeout: 30 * time.Second,
+	RestartBackoff:      time.Second,
+	MaxRestartBackoff:   time.Minute,
+}
+
+type Config struct {
+	ShutdownWaitTimeout time.Duration `mapstructure:"shutdown_wait_timeout" validate:"gte=0"` // ShutdownWaitTimeout 等待全部组件退出的最长时间
+	RestartBackoff      time.Duration `mapstructure:"restart_backoff" validate:"gt=0"`        // RestartBackoff 组件首次重启前的等待时间 之后每次翻倍 不可为0 避免立即重启的死循环
+	MaxRestartBackoff   time.Duration `mapstructure:"max_restart_backoff" validate:"gt=0"`    // MaxRestartBackoff 组件重启等待时间上限
+}
@@ -0,0 +1,293 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/crontab"
+	"github.com/zjutjh/mygo/foundation/httpserver"
+	"github.com/zjutjh/mygo/foundation/kernel"
+)
+
+func init() {
+	kernel.RegisterOptionalConfig("supervisor", DefaultConfig)
+}
+
+// Runner 受监管的组件
+// Run 应阻塞运行 ctx取消时优雅退出并返回nil 运行失败时返回错误
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// RunnerFunc 函数形式的Runner
+type RunnerFunc func(ctx context.Context) error
+
+// Run 实现Runner
+func (f RunnerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Policy 组件失败 (返回错误或panic) 时的处理策略
+type Policy int
+
+const (
+	// Escalate 关闭全部组件 Supervisor返回该错误 (默认)
+	Escalate Policy = iota
+	// Restart 按退避间隔重启组件 超过最大重启次数后Escalate
+	Restart
+	// Ignore 仅记录错误 组件不再运行 其他组件不受影响
+	Ignore
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Escalate:
+		return "escalate"
+	case Restart:
+		return "restart"
+	case Ignore:
+		return "ignore"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// component 受监管的组件及其策略
+type component struct {
+	name        string
+	runner      Runner
+	policy      Policy
+	maxRestarts int
+}
+
+// Option 组件选项
+type Option func(*component)
+
+// WithPolicy 设置组件失败时的处理策略
+func WithPolicy(p Policy) Option {
+	return func(c *component) {
+		c.policy = p
+	}
+}
+
+// WithRestart 组件失败时重启 最多重启max次 (max<=0时不限次数) 超过后Escalate
+func WithRestart(max int) Option {
+	return func(c *component) {
+		c.policy = Restart
+		c.maxRestarts = max
+	}
+}
+
+// Supervisor 在同一进程中运行多个组件 统一处理结束信号与优雅关闭
+type Supervisor struct {
+	conf       Config
+	components []*component
+}
+
+// New 按config.yaml[supervisor]创建Supervisor 配置解析失败时返回错误 (可通过errors.Is判断kernel.ErrConfig)
+func New() (*Supervisor, error) {
+	conf := DefaultConfig
+	if err := config.Pick().UnmarshalKey("supervisor", &conf); err != nil {
+		return nil, fmt.Errorf("%w: 解析配置[supervisor]错误: %w", kernel.ErrConfig, err)
+	}
+	return &Supervisor{conf: conf}, nil
+}
+
+// Add 添加组件 组件名不可重复
+func (s *Supervisor) Add(name string, r Runner, opts ...Option) {
+	c := &component{name: name, runner: r}
+	for _, opt := range opts {
+		opt(c)
+	}
+	s.components = append(s.components, c)
+}
+
+// AddHTTPServer 添加HTTP Server组件
+func (s *Supervisor) AddHTTPServer(routeRegister func(*gin.Engine), opts ...Option) error {
+	srv, err := httpserver.NewServer(routeRegister)
+	if err != nil {
+		return err
+	}
+	s.Add("http_server", srv, opts...)
+	return nil
+}
+
+// AddCron 添加定时任务组件
+func (s *Supervisor) AddCron(jobRegister func(*cron.Cron), opts ...Option) error {
+	e, err := crontab.NewEngine(jobRegister)
+	if err != nil {
+		return err
+	}
+	s.Add("cron", e, opts...)
+	return nil
+}
+
+// RunWithSignals 运行全部组件 收到SIGINT/SIGTERM时优雅关闭 运行期间同时处理运行时运维信号
+// 全部组件退出后关闭全部已引导资源 (日志最后关闭)
+func (s *Supervisor) RunWithSignals() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	stopRuntime := kernel.ListenRuntimeSignals()
+	defer stopRuntime()
+	err := s.Run(ctx)
+	if cerr := kernel.Shutdown(context.Background()); cerr != nil {
+		err = errors.Join(err, fmt.Errorf("关闭资源错误: %w", cerr))
+	}
+	return err
+}
+
+// Run 运行全部组件并阻塞 全部组件结束运行 (且无待重启的组件) 时返回
+// ctx取消或组件失败升级 (Escalate) 时通知全部组件退出
+// 并最多等待shutdown_wait_timeout 返回升级的错误与未按时退出的组件
+func (s *Supervisor) Run(ctx context.Context) error {
+	names := map[string]bool{}
+	for _, c := range s.components {
+		if names[c.name] {
+			return fmt.Errorf("组件[%s]重复添加", c.name)
+		}
+		names[c.name] = true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		escalated []error
+		running   = map[string]bool{}
+	)
+	for _, c := range s.components {
+		running[c.name] = true
+	}
+	for _, c := range s.components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.supervise(ctx, c)
+			mu.Lock()
+			defer mu.Unlock()
+			delete(running, c.name)
+			if err != nil {
+				escalated = append(escalated, err)
+				cancel()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// 等待退出信号、组件升级或全部组件结束运行 (重启中的组件在supervise返回前不算结束)
+	select {
+	case <-done:
+		fmt.Fprintln(kernel.Output(), "Supervisor全部组件已结束运行")
+		mu.Lock()
+		defer mu.Unlock()
+		return errors.Join(escalated...)
+	case <-ctx.Done():
+	}
+	fmt.Fprintln(kernel.Output(), "Supervisor开始关闭全部组件")
+
+	timer := time.NewTimer(s.conf.ShutdownWaitTimeout)
+	defer timer.Stop()
+
+	var errs []error
+	select {
+	case <-done:
+		fmt.Fprintln(kernel.Output(), "Supervisor关闭完成")
+	case <-timer.C:
+		mu.Lock()
+		left := make([]string, 0, len(running))
+		for name := range running {
+			left = append(left, name)
+		}
+		mu.Unlock()
+		sort.Strings(left)
+		errs = append(errs, fmt.Errorf("等待组件退出超时, 未退出的组件: %s", strings.Join(left, ", ")))
+	}
+
+	mu.Lock()
+	errs = append(escalated, errs...)
+	mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// supervise 按策略运行单个组件 需要升级时返回错误
+func (s *Supervisor) supervise(ctx context.Context, c *component) error {
+	backoff := s.conf.RestartBackoff
+	for restarts := 0; ; restarts++ {
+		err := runSafely(ctx, c.runner)
+		if ctx.Err() != nil {
+			if err != nil {
+				fmt.Fprintf(kernel.Output(), "组件[%s]关闭错误: %s\n", c.name, err)
+			}
+			return nil
+		}
+		if err == nil {
+			fmt.Fprintf(kernel.Output(), "组件[%s]已结束运行\n", c.name)
+			return nil
+		}
+
+		fmt.Fprintf(kernel.Output(), "组件[%s]运行失败(策略: %s): %s\n", c.name, c.policy, err)
+		switch c.policy {
+		case Ignore:
+			return nil
+		case Restart:
+			if c.maxRestarts <= 0 || restarts < c.maxRestarts {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(backoff):
+				}
+				backoff = min(backoff*2, s.conf.MaxRestartBackoff)
+				fmt.Fprintf(kernel.Output(), "组件[%s]第%d次重启\n", c.name, restarts+1)
+				continue
+			}
+			return fmt.Errorf("组件[%s]重启%d次后仍然失败: %w", c.name, restarts, err)
+		}
+		return fmt.Errorf("组件[%s]运行失败: %w", c.name, err)
+	}
+}
+
+// runSafely 运行组件 将panic转换为错误
+func runSafely(ctx context.Context, r Runner) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			err = fmt.Errorf("panic: %v\n%s", p, buf)
+		}
+	}()
+	return r.Run(ctx)
+}
+
+// CommandRegister 以Supervisor运行多个组件的命令注册
+func CommandRegister(register func(s *Supervisor) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		s, err := New()
+		if err != nil {
+			return err
+		}
+		if err := register(s); err != nil {
+			return err
+		}
+		return s.RunWithSignals()
+	}
+}
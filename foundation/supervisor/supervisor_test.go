@@ -0,0 +1,78 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunReturnsWhenComponentsFinish(t *testing.T) {
+	failTwice := func() Runner {
+		var n atomic.Int32
+		return RunnerFunc(func(ctx context.Context) error {
+			if n.Add(1) <= 2 {
+				return errors.New("boom")
+			}
+			return nil
+		})
+	}
+	nop := RunnerFunc(func(ctx context.Context) error { return nil })
+	fail := RunnerFunc(func(ctx context.Context) error { return errors.New("boom") })
+	block := RunnerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	tests := []struct {
+		name    string
+		add     func(s *Supervisor)
+		wantErr string
+	}{
+		{"全部组件正常结束", func(s *Supervisor) {
+			s.Add("a", nop)
+			s.Add("b", nop)
+		}, ""},
+		{"忽略失败的组件", func(s *Supervisor) {
+			s.Add("a", nop)
+			s.Add("b", fail, WithPolicy(Ignore))
+		}, ""},
+		{"重启后正常结束", func(s *Supervisor) {
+			s.Add("a", failTwice(), WithRestart(0))
+		}, ""},
+		{"超过重启次数后升级", func(s *Supervisor) {
+			s.Add("a", fail, WithRestart(1))
+			s.Add("b", block)
+		}, "组件[a]重启1次后仍然失败: boom"},
+		{"失败升级时关闭其他组件", func(s *Supervisor) {
+			s.Add("a", fail)
+			s.Add("b", block)
+		}, "组件[a]运行失败: boom"},
+		{"组件名重复", func(s *Supervisor) {
+			s.Add("a", nop)
+			s.Add("a", nop)
+		}, "组件[a]重复添加"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := DefaultConfig
+			conf.RestartBackoff = time.Millisecond
+			conf.ShutdownWaitTimeout = time.Second
+			s := &Supervisor{conf: conf}
+			tt.add(s)
+
+			errc := make(chan error, 1)
+			go func() { errc <- s.Run(context.Background()) }()
+			select {
+			case err := <-errc:
+				if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+					t.Fatalf("Run() = %v, want %q", err, tt.wantErr)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Run() did not return after all components finished")
+			}
+		})
+	}
+}
@@ -14,8 +14,8 @@ var DefaultConfig = Config{
 }
 
 type Config struct {
-	Secret     string           `mapstructure:"secret"`
-	Expiration time.Duration    `mapstructure:"expiration"`
+	Secret     string           `mapstructure:"secret" validate:"required"`
+	Expiration time.Duration    `mapstructure:"expiration" validate:"gt=0"`
 	Issuer     string           `mapstructure:"issuer"`
 	Audience   jwt.ClaimStrings `mapstructure:"audience"`
 }
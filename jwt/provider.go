@@ -2,11 +2,13 @@ package jwt
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jinzhu/copier"
 	"github.com/samber/do"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 )
 
@@ -16,8 +18,13 @@ const (
 )
 
 // Boot 预加载默认实例 同时加载指定实例列表
-func Boot[T any](scopes ...string) func() error {
-	return func() error {
+// 引导步骤名为jwt
+func Boot[T any](scopes ...string) kernel.Step {
+	kernel.RegisterConfig(defaultScope, DefaultConfig)
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope, func() error {
 		if err := provide[T](defaultScope); err != nil {
 			return fmt.Errorf("加载资源[%s]错误: %w", defaultScope, err)
 		}
@@ -27,19 +34,22 @@ func Boot[T any](scopes ...string) func() error {
 			}
 		}
 		return nil
-	}
+	})
 }
 
-// BootCustom 仅加载指定实例列表
-func BootCustom[T any](scopes ...string) func() error {
-	return func() error {
+// BootCustom 仅加载指定实例列表 引导步骤名为jwt:{scopes}
+func BootCustom[T any](scopes ...string) kernel.Step {
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope+":"+strings.Join(scopes, ","), func() error {
 		for _, scope := range scopes {
 			if err := provide[T](scope); err != nil {
 				return fmt.Errorf("加载资源[%s]错误: %w", scope, err)
 			}
 		}
 		return nil
-	}
+	})
 }
 
 // Exist 判断scope实例是否挂载 (被Boot过) 且类型正确
@@ -0,0 +1,48 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/zjutjh/mygo/nedis"
+)
+
+// OwnerValue 生成带持有者标识的锁值 形如{host}:{pid}:{随机串} 配合redsync.WithGenValueFunc使用
+func OwnerValue() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// Owner 获取key当前持有者标识 即OwnerValue生成的锁值去除随机串部分 未被持有时返回空串
+func Owner(ctx context.Context, key string, scopes ...string) (string, error) {
+	scope := defaultScope
+	if len(scopes) != 0 && scopes[0] != "" {
+		scope = scopes[0]
+	}
+	conf, err := getConf(scope)
+	if err != nil {
+		return "", err
+	}
+	value, err := nedis.Pick(conf.Redis).Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if i := strings.LastIndex(value, ":"); i != -1 {
+		return value[:i], nil
+	}
+	return value, nil
+}
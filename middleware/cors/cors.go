@@ -1,35 +1,58 @@
 package cors
 
 import (
+	"fmt"
+	"sync/atomic"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
+	"github.com/spf13/viper"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 )
 
 const defaultConfigKey = "mid_cors"
 
+func init() {
+	kernel.RegisterOptionalConfig(defaultConfigKey, DefaultConfig)
+}
+
 // Pick 获取指定实例
+// 配置变更时自动按新配置重建, 已挂载的中间件无需重新注册
 func Pick(keys ...string) gin.HandlerFunc {
 	key := defaultConfigKey
 	if len(keys) != 0 && keys[0] != "" {
 		key = keys[0]
 	}
-	conf := Config{}
-	err := copier.Copy(&conf, DefaultConfig)
+	conf, err := getConf(key)
 	if err != nil {
 		panic(err)
 	}
-	app := config.Pick()
-	if !app.IsSet(key) {
-		panic(kit.ErrNotFound)
-	}
-	err = app.UnmarshalKey(key, &conf)
-	if err != nil {
-		panic(err)
+
+	var handler atomic.Pointer[gin.HandlerFunc]
+	h := New(conf)
+	handler.Store(&h)
+
+	config.OnChange("", key, func(*viper.Viper) error {
+		conf, err := getConf(key)
+		if err != nil {
+			return err
+		}
+		h := New(conf)
+		handler.Store(&h)
+		return nil
+	})
+
+	return func(ctx *gin.Context) {
+		(*handler.Load())(ctx)
 	}
+}
+
+// New 以指定配置创建实例
+func New(conf Config) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowAllOrigins:           conf.AllowAllOrigins,
 		AllowOrigins:              conf.AllowOrigins,
@@ -47,3 +70,20 @@ func Pick(keys ...string) gin.HandlerFunc {
 		OptionsResponseStatusCode: conf.OptionsResponseStatusCode,
 	})
 }
+
+// getConf 获取配置
+func getConf(key string) (conf Config, err error) {
+	err = copier.Copy(&conf, DefaultConfig)
+	if err != nil {
+		return conf, err
+	}
+	app := config.Pick()
+	if !app.IsSet(key) {
+		return conf, fmt.Errorf("%w: 配置config.yaml[%s]不存在", kit.ErrNotFound, key)
+	}
+	err = app.UnmarshalKey(key, &conf)
+	if err != nil {
+		return conf, fmt.Errorf("%w: 解析config.yaml[%s]错误: %w", kit.ErrDataUnmarshal, key, err)
+	}
+	return conf, nil
+}
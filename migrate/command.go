@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/command"
+)
+
+var migrateUpFlags = &struct {
+	DB    string `flag:"db" usage:"ndb instance scope (default is db)"`
+	Steps int    `flag:"steps" short:"n" usage:"max migrations to apply, 0 means all" validate:"gte=0"`
+}{}
+
+var migrateDownFlags = &struct {
+	DB    string `flag:"db" usage:"ndb instance scope (default is db)"`
+	Steps int    `flag:"steps" short:"n" usage:"migrations to revert" validate:"gte=1"`
+	All   bool   `flag:"all" usage:"revert all applied migrations"`
+}{Steps: 1}
+
+var migrateStatusFlags = &struct {
+	DB string `flag:"db" usage:"ndb instance scope (default is db)"`
+}{}
+
+// newCreateCommand 创建迁移文件命令 无需引导资源 不经过command的引导流程
+func newCreateCommand() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:           "create <name>",
+		Short:         "创建SQL迁移文件",
+		Long:          "在迁移目录 (--dir 默认为config.yaml[migrate.dir]) 下创建一对空的{version}_{name}.up.sql/.down.sql文件 版本号为当前UTC时间 (无需引导资源)",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 未指定--dir时使用config.yaml[migrate.dir] 配置不可用时使用默认目录
+			if f := cmd.Flag("config"); f != nil && !cmd.Flags().Changed("dir") && config.Boot(f.Value.String()) == nil {
+				conf := DefaultConfig
+				if err := config.Pick().UnmarshalKey("migrate", &conf); err == nil && conf.Dir != "" {
+					dir = conf.Dir
+				}
+			}
+			up, down, err := Create(dir, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), up)
+			fmt.Fprintln(cmd.OutOrStdout(), down)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", DefaultConfig.Dir, "migrations directory")
+	return cmd
+}
+
+func migrateUp(cmd *cobra.Command, args []string) error {
+	m, err := NewScope(migrateUpFlags.DB, WithOutput(cmd.OutOrStdout()))
+	if err != nil {
+		return err
+	}
+	applied, err := m.Up(cmd.Context(), migrateUpFlags.Steps)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "没有需要执行的迁移")
+	}
+	return nil
+}
+
+func migrateDown(cmd *cobra.Command, args []string) error {
+	m, err := NewScope(migrateDownFlags.DB, WithOutput(cmd.OutOrStdout()))
+	if err != nil {
+		return err
+	}
+	steps := migrateDownFlags.Steps
+	if migrateDownFlags.All {
+		steps = 0
+	}
+	reverted, err := m.Down(cmd.Context(), steps)
+	if err != nil {
+		return err
+	}
+	if len(reverted) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "没有可回滚的迁移")
+	}
+	return nil
+}
+
+func migrateStatus(cmd *cobra.Command, args []string) error {
+	m, err := NewScope(migrateStatusFlags.DB)
+	if err != nil {
+		return err
+	}
+	list, err := m.Status(cmd.Context())
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tSOURCE")
+	for _, s := range list {
+		status, at := "pending", "-"
+		if s.Applied {
+			status, at = "applied", s.AppliedAt.Format(time.DateTime)
+		}
+		if s.Missing {
+			status = "applied (missing)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.Version, s.Name, status, at, s.Source)
+	}
+	return w.Flush()
+}
+
+// Command 数据库迁移命令 (app migrate up|down|status|create) 迁移通过RegisterFS/RegisterFunc注册
+// 引入migrate包即作为内置命令挂载到根命令 (见init) 也可挂载到其他父命令下
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "数据库迁移",
+		Long:  "按版本执行或回滚通过migrate.RegisterFS/RegisterFunc注册的数据库迁移 (需在BootList中引导对应的ndb实例)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(
+		command.New("up", migrateUp,
+			command.WithShort("执行未执行的迁移"),
+			command.WithArgs(cobra.NoArgs),
+			command.WithFlags(migrateUpFlags),
+			command.WithExample("app migrate up --db db -n 1"),
+		),
+		command.New("down", migrateDown,
+			command.WithShort("回滚已执行的迁移 (默认回滚最近1个)"),
+			command.WithArgs(cobra.NoArgs),
+			command.WithFlags(migrateDownFlags),
+			command.WithExample("app migrate down -n 2\napp migrate down --all"),
+		),
+		command.New("status", migrateStatus,
+			command.WithShort("查看迁移执行状态"),
+			command.WithArgs(cobra.NoArgs),
+			command.WithFlags(migrateStatusFlags),
+		),
+		newCreateCommand(),
+	)
+	return cmd
+}
+
+func init() {
+	command.AddBuiltin(Command())
+}
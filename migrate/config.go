@@ -0,0 +1,15 @@
+package migrate
+
+import "time"
+
+var DefaultConfig = Config{
+	Table:       "schema_migrations",
+	LockTimeout: 10 * time.Second,
+	Dir:         "migrations",
+}
+
+type Config struct {
+	Table       string        `mapstructure:"table" validate:"required"`     // Table 迁移历史表名
+	LockTimeout time.Duration `mapstructure:"lock_timeout" validate:"gte=0"` // LockTimeout 等待迁移锁的最长时间 0表示不等待
+	Dir         string        `mapstructure:"dir"`                           // Dir migrate create创建迁移文件的目录
+}
@@ -0,0 +1,57 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Create 在dir下创建一对空的SQL迁移文件 版本号为当前UTC时间 形如20260101120000
+// name中非字母数字的字符会被替换为下划线 返回创建的up与down文件路径
+func Create(dir, name string) (up, down string, err error) {
+	name = sanitize(name)
+	if name == "" {
+		return "", "", errors.New("迁移名不能为空")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("创建迁移目录[%s]错误: %w", dir, err)
+	}
+	prefix := fmt.Sprintf("%s_%s", time.Now().UTC().Format("20060102150405"), name)
+	up = filepath.Join(dir, prefix+".up.sql")
+	down = filepath.Join(dir, prefix+".down.sql")
+	for _, f := range []struct {
+		path, comment string
+	}{
+		{up, "-- 迁移: " + prefix + "\n"},
+		{down, "-- 回滚: " + prefix + "\n"},
+	} {
+		w, err := os.OpenFile(f.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("创建迁移文件[%s]错误: %w", f.path, err)
+		}
+		_, err = w.WriteString(f.comment)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("写入迁移文件[%s]错误: %w", f.path, err)
+		}
+	}
+	return up, down, nil
+}
+
+func sanitize(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,207 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// defaultScope 与ndb默认实例scope一致
+const defaultScope = "db"
+
+// Func Go函数迁移 tx为迁移所在事务
+type Func func(ctx context.Context, tx *gorm.DB) error
+
+// Migration 单个版本迁移 同一方向的Go函数与SQL二选一 Go函数优先
+type Migration struct {
+	Version int64  // Version 版本号 通常为创建时间 如20260101120000
+	Name    string // Name 迁移名
+	Source  string // Source 迁移来源 SQL文件路径或go
+
+	Up      Func
+	Down    Func
+	UpSQL   string
+	DownSQL string
+}
+
+// String 形如20260101120000_create_users
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// reversible 是否可回滚
+func (m Migration) reversible() bool {
+	return m.Down != nil || m.DownSQL != ""
+}
+
+// fileName SQL迁移文件名 {version}_{name}.up.sql / {version}_{name}.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// FromFS 读取fsys中dir目录下的SQL迁移文件 (通常为embed.FS)
+// 文件名形如20260101120000_create_users.up.sql 与对应的.down.sql (可省略 省略时不可回滚)
+func FromFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录[%s]错误: %w", dir, err)
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("迁移文件[%s]版本号错误: %w", e.Name(), err)
+		}
+		file := path.Join(dir, e.Name())
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件[%s]错误: %w", file, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2], Source: file}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("迁移版本[%d]存在不同名称的文件: %s, %s", version, m.Name, match[2])
+		}
+		switch match[3] {
+		case "up":
+			m.UpSQL = string(content)
+			m.Source = file
+		case "down":
+			m.DownSQL = string(content)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("迁移[%s]缺少up文件", m)
+		}
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].Version < ms[j].Version
+	})
+	return ms, nil
+}
+
+type source struct {
+	fsys fs.FS
+	dir  string
+}
+
+type entry struct {
+	sources []source
+	funcs   []Migration
+}
+
+var (
+	registry   = map[string]*entry{}
+	registryMu sync.Mutex
+)
+
+// RegisterFS 为ndb实例scope注册SQL迁移目录 scope为空时为默认实例
+// 目录在执行迁移时才读取 一般在init中以embed.FS注册
+func RegisterFS(scope string, fsys fs.FS, dir string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e := entryOf(scope)
+	e.sources = append(e.sources, source{fsys: fsys, dir: dir})
+}
+
+// RegisterFunc 为ndb实例scope注册Go函数迁移 scope为空时为默认实例 down为nil时不可回滚
+func RegisterFunc(scope string, version int64, name string, up, down Func) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e := entryOf(scope)
+	e.funcs = append(e.funcs, Migration{Version: version, Name: name, Source: "go", Up: up, Down: down})
+}
+
+// Migrations 汇总ndb实例scope下注册的全部迁移 按版本升序 版本重复时报错
+func Migrations(scope string) ([]Migration, error) {
+	registryMu.Lock()
+	e, ok := registry[normalize(scope)]
+	var sources []source
+	var funcs []Migration
+	if ok {
+		sources = append(sources, e.sources...)
+		funcs = append(funcs, e.funcs...)
+	}
+	registryMu.Unlock()
+
+	ms := funcs
+	for _, s := range sources {
+		list, err := FromFS(s.fsys, s.dir)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, list...)
+	}
+	return sorted(ms)
+}
+
+// sorted 按版本升序排列 并校验版本唯一与Up存在
+func sorted(ms []Migration) ([]Migration, error) {
+	ms = append([]Migration(nil), ms...)
+	sort.SliceStable(ms, func(i, j int) bool {
+		return ms[i].Version < ms[j].Version
+	})
+	for i, m := range ms {
+		if m.Up == nil && m.UpSQL == "" {
+			return nil, fmt.Errorf("迁移[%s]未设置Up", m)
+		}
+		if i > 0 && ms[i-1].Version == m.Version {
+			return nil, fmt.Errorf("迁移版本[%d]重复: %s(%s), %s(%s)", m.Version, ms[i-1], ms[i-1].Source, m, m.Source)
+		}
+	}
+	return ms, nil
+}
+
+// reset 重置迁移注册表 已注册的迁移复制到重置后的注册表 见kernel.Reset
+func reset() (restore func()) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	prev := registry
+	registry = make(map[string]*entry, len(prev))
+	for scope, e := range prev {
+		registry[scope] = &entry{sources: slices.Clone(e.sources), funcs: slices.Clone(e.funcs)}
+	}
+	return func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		registry = prev
+	}
+}
+
+func entryOf(scope string) *entry {
+	scope = normalize(scope)
+	e, ok := registry[scope]
+	if !ok {
+		e = &entry{}
+		registry[scope] = e
+	}
+	return e
+}
+
+func normalize(scope string) string {
+	if scope == "" {
+		return defaultScope
+	}
+	return scope
+}
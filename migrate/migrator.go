@@ -0,0 +1,310 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
+	"github.com/zjutjh/mygo/ndb"
+)
+
+// ErrLocked 迁移锁被其他执行者持有
+var ErrLocked = errors.New("其他迁移正在执行")
+
+// Option 迁移执行器配置项
+type Option func(*Migrator)
+
+// WithTable 迁移历史表名 默认为schema_migrations
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithLockTimeout 等待迁移锁的最长时间 默认为10s
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
+}
+
+// WithOutput 输出执行进度 默认不输出
+func WithOutput(w io.Writer) Option {
+	return func(m *Migrator) {
+		m.out = w
+	}
+}
+
+// Migrator 迁移执行器 执行期间以MySQL GET_LOCK防止同一数据库上的并发迁移
+type Migrator struct {
+	db          *gorm.DB
+	migrations  []Migration
+	table       string
+	lockTimeout time.Duration
+	out         io.Writer
+}
+
+// Status 单个迁移的执行状态
+type Status struct {
+	Version   int64     `json:"version"`
+	Name      string    `json:"name"`
+	Source    string    `json:"source"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"applied_at,omitzero"`
+	// Missing 历史表中已执行 但迁移未注册
+	Missing bool `json:"missing,omitempty"`
+}
+
+// record 迁移历史记录
+type record struct {
+	Version    int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name       string    `gorm:"column:name"`
+	AppliedAt  time.Time `gorm:"column:applied_at"`
+	DurationMs int64     `gorm:"column:duration_ms"`
+}
+
+// New 以指定迁移创建执行器
+func New(db *gorm.DB, migrations []Migration, opts ...Option) (*Migrator, error) {
+	ms, err := sorted(migrations)
+	if err != nil {
+		return nil, err
+	}
+	m := &Migrator{
+		db:          db,
+		migrations:  ms,
+		table:       DefaultConfig.Table,
+		lockTimeout: DefaultConfig.LockTimeout,
+		out:         io.Discard,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// NewScope 按config.yaml[migrate]创建ndb实例scope的执行器 迁移为该scope下注册的全部迁移
+// scope为空时为默认实例 ndb实例需已引导
+func NewScope(scope string, opts ...Option) (*Migrator, error) {
+	conf := DefaultConfig
+	if err := config.Pick().UnmarshalKey("migrate", &conf); err != nil {
+		return nil, fmt.Errorf("解析config.yaml[migrate]错误: %w", err)
+	}
+	scope = normalize(scope)
+	if !ndb.Exist(scope) {
+		return nil, fmt.Errorf("ndb实例[%s]未引导", scope)
+	}
+	ms, err := Migrations(scope)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]Option{WithTable(conf.Table), WithLockTimeout(conf.LockTimeout)}, opts...)
+	return New(ndb.Pick(scope), ms, opts...)
+}
+
+// Up 按版本升序执行未执行的迁移 steps为最多执行的数量 0表示全部
+// 每个迁移与其历史记录在同一事务中执行 (MySQL的DDL会隐式提交 失败时可能需要人工处理)
+// 返回已成功执行的迁移 遇到错误时停止
+func (m *Migrator) Up(ctx context.Context, steps int) (applied []Migration, err error) {
+	err = m.withLock(ctx, func() error {
+		done, err := m.history(ctx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if steps > 0 && len(applied) >= steps {
+				break
+			}
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig, true); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按版本降序回滚已执行的迁移 steps为回滚的数量 0表示全部
+// 返回已成功回滚的迁移 遇到错误时停止
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	err = m.withLock(ctx, func() error {
+		done, err := m.history(ctx)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(done))
+		for v := range done {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+		for _, v := range versions {
+			if steps > 0 && len(reverted) >= steps {
+				break
+			}
+			mig, ok := m.find(v)
+			if !ok {
+				return fmt.Errorf("迁移[%d_%s]已执行但未注册, 无法回滚", v, done[v].Name)
+			}
+			if !mig.reversible() {
+				return fmt.Errorf("迁移[%s]未设置Down, 无法回滚", mig)
+			}
+			if err := m.apply(ctx, mig, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status 全部迁移的执行状态 按版本升序 包含已执行但未注册的迁移
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name, Source: mig.Source}
+		if r, ok := done[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = r.AppliedAt
+			delete(done, mig.Version)
+		}
+		list = append(list, s)
+	}
+	for _, r := range done {
+		list = append(list, Status{Version: r.Version, Name: r.Name, Applied: true, AppliedAt: r.AppliedAt, Missing: true})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+// apply 在事务中执行单个迁移并更新历史记录
+func (m *Migrator) apply(ctx context.Context, mig Migration, up bool) error {
+	action, fn, stmts := "执行", mig.Up, mig.UpSQL
+	if !up {
+		action, fn, stmts = "回滚", mig.Down, mig.DownSQL
+	}
+	fmt.Fprintf(m.out, "%s迁移[%s]\n", action, mig)
+
+	start := time.Now()
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if fn != nil {
+			if err := fn(ctx, tx); err != nil {
+				return err
+			}
+		} else {
+			for i, stmt := range splitStatements(stmts) {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return fmt.Errorf("第%d条语句错误: %w", i+1, err)
+				}
+			}
+		}
+		if !up {
+			return tx.Table(m.table).Where("version = ?", mig.Version).Delete(&record{}).Error
+		}
+		return tx.Table(m.table).Create(&record{
+			Version:    mig.Version,
+			Name:       mig.Name,
+			AppliedAt:  time.Now(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s迁移[%s]错误: %w", action, mig, err)
+	}
+	fmt.Fprintf(m.out, "%s迁移[%s]完成, 耗时[%s]\n", action, mig, time.Since(start))
+	return nil
+}
+
+// ensureTable 创建迁移历史表
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	err := m.db.WithContext(ctx).Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` ("+
+		"`version` BIGINT NOT NULL PRIMARY KEY, "+
+		"`name` VARCHAR(255) NOT NULL, "+
+		"`applied_at` DATETIME(3) NOT NULL, "+
+		"`duration_ms` BIGINT NOT NULL DEFAULT 0)", m.table)).Error
+	if err != nil {
+		return fmt.Errorf("创建迁移历史表[%s]错误: %w", m.table, err)
+	}
+	return nil
+}
+
+// history 已执行的迁移 按版本索引
+func (m *Migrator) history(ctx context.Context) (map[int64]record, error) {
+	var rs []record
+	if err := m.db.WithContext(ctx).Table(m.table).Order("version").Find(&rs).Error; err != nil {
+		return nil, fmt.Errorf("查询迁移历史表[%s]错误: %w", m.table, err)
+	}
+	done := make(map[int64]record, len(rs))
+	for _, r := range rs {
+		done[r.Version] = r
+	}
+	return done, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+// withLock 持有迁移锁执行fn 锁以数据库名与历史表名区分 连接断开时由MySQL自动释放
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return fmt.Errorf("获取DB实例错误: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取迁移锁连接错误: %w", err)
+	}
+	defer conn.Close()
+
+	name := "mygo_migrate:" + m.table + ":"
+	var got sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(?, DATABASE()), ?)", name, int(m.lockTimeout.Seconds())).Scan(&got)
+	if err != nil {
+		return fmt.Errorf("获取迁移锁错误: %w", err)
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return fmt.Errorf("%w: 等待[%s]后仍未获取到迁移锁", ErrLocked, m.lockTimeout)
+	}
+	defer func() {
+		var released sql.NullInt64
+		_ = conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(CONCAT(?, DATABASE()))", name).Scan(&released)
+	}()
+
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+	return fn()
+}
+
+func init() {
+	kernel.RegisterOptionalConfig("migrate", DefaultConfig)
+	kernel.OnReset("migrate", reset)
+}
@@ -0,0 +1,60 @@
+package migrate
+
+import "strings"
+
+// splitStatements 按分号拆分SQL语句 忽略引号内与注释中的分号 去除空语句
+// MySQL驱动默认不允许一次执行多条语句 因此逐条执行
+func splitStatements(sql string) []string {
+	var (
+		stmts []string
+		b     strings.Builder
+		quote byte
+	)
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		b.Reset()
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if quote != 0 {
+			b.WriteByte(c)
+			switch {
+			case c == '\\' && quote != '`' && i+1 < len(sql):
+				i++
+				b.WriteByte(sql[i])
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case c == '#' || (c == '-' && strings.HasPrefix(sql[i:], "-- ")):
+			// 单行注释
+			if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+				i += j
+				b.WriteByte('\n')
+			} else {
+				i = len(sql)
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			// 多行注释
+			if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+				i += j + 3
+			} else {
+				i = len(sql)
+			}
+			b.WriteByte(' ')
+		case c == ';':
+			flush()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return stmts
+}
@@ -0,0 +1,53 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "按分号拆分并去除空语句",
+			sql:  "CREATE TABLE a (id INT);\n\n;INSERT INTO a VALUES (1);  ",
+			want: []string{"CREATE TABLE a (id INT)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			name: "忽略引号内的分号",
+			sql:  `INSERT INTO a VALUES ('x;y', "p;q"); SELECT ` + "`a;b`" + ` FROM a`,
+			want: []string{`INSERT INTO a VALUES ('x;y', "p;q")`, "SELECT `a;b` FROM a"},
+		},
+		{
+			name: "引号内的转义字符",
+			sql:  `INSERT INTO a VALUES ('it\'s;ok', 'a\\'); SELECT 1`,
+			want: []string{`INSERT INTO a VALUES ('it\'s;ok', 'a\\')`, "SELECT 1"},
+		},
+		{
+			name: "忽略注释中的分号",
+			sql:  "-- drop; table\nSELECT 1; # note; here\nSELECT /* a; b */ 2;",
+			want: []string{"SELECT 1", "SELECT   2"},
+		},
+		{
+			name: "双横线后无空格不视为注释",
+			sql:  "SELECT 1--1; SELECT 2",
+			want: []string{"SELECT 1--1", "SELECT 2"},
+		},
+		{
+			name: "未闭合的注释",
+			sql:  "SELECT 1; /* SELECT 2;",
+			want: []string{"SELECT 1"},
+		},
+		{name: "仅包含注释", sql: "-- only comment", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
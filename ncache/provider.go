@@ -2,6 +2,8 @@ package ncache
 
 import (
 	"github.com/samber/do"
+
+	"github.com/zjutjh/mygo/foundation/kernel"
 )
 
 const (
@@ -10,8 +12,9 @@ const (
 )
 
 // Boot 默认引导器，注册一个或多个内存缓存实例
-func Boot(scopes ...string) func() error {
-	return func() error {
+// 引导步骤名为cache
+func Boot(scopes ...string) kernel.Step {
+	return kernel.NewStep(defaultScope, func() error {
 		// 如果未指定 scope，则使用默认的 scope
 		if len(scopes) == 0 {
 			scopes = []string{defaultScope}
@@ -26,7 +29,7 @@ func Boot(scopes ...string) func() error {
 		}
 
 		return nil
-	}
+	})
 }
 
 // Pick 获取缓存实例
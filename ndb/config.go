@@ -58,12 +58,12 @@ var DefaultConfig = Config{
 
 type Config struct {
 	// 基础系列
-	Host      string `mapstructure:"host"`
-	Port      int    `mapstructure:"port"`
-	Database  string `mapstructure:"database"`
-	Username  string `mapstructure:"username"`
+	Host      string `mapstructure:"host" validate:"required"`
+	Port      int    `mapstructure:"port" validate:"min=1,max=65535"`
+	Database  string `mapstructure:"database" validate:"required"`
+	Username  string `mapstructure:"username" validate:"required"`
 	Password  string `mapstructure:"password"`
-	Charset   string `mapstructure:"charset"`
+	Charset   string `mapstructure:"charset" validate:"required"`
 	ParseTime string `mapstructure:"parse_time"`
 	Loc       string `mapstructure:"loc"`
 
@@ -98,15 +98,15 @@ type Config struct {
 	// gorm logger系列
 	OpenLogger                bool            `mapstructure:"open_logger"`
 	Log                       string          `mapstructure:"log"`
-	SlowThreshold             time.Duration   `mapstructure:"slow_threshold"`
+	SlowThreshold             time.Duration   `mapstructure:"slow_threshold" validate:"gte=0"`
 	Colorful                  bool            `mapstructure:"colorful"`
 	IgnoreRecordNotFoundError bool            `mapstructure:"ignore_record_not_found_error"`
 	ParameterizedQueries      bool            `mapstructure:"parameterized_queries"`
-	LogLevel                  logger.LogLevel `mapstructure:"log_level"`
+	LogLevel                  logger.LogLevel `mapstructure:"log_level" validate:"min=1,max=4"`
 
 	// sql系列
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" validate:"gte=0"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns" validate:"gte=0"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
 	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
 }
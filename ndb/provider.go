@@ -1,6 +1,7 @@
 package ndb
 
 import (
+	"context"
 	"fmt"
 
 	"gorm.io/gorm"
@@ -9,6 +10,7 @@ import (
 	"github.com/samber/do"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 )
 
@@ -18,8 +20,13 @@ const (
 )
 
 // Boot 预加载默认实例 同时加载指定实例列表
-func Boot(scopes ...string) func() error {
-	return func() error {
+// 引导步骤名为db 依赖log
+func Boot(scopes ...string) kernel.Step {
+	kernel.RegisterConfig(defaultScope, DefaultConfig)
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope, func() error {
 		if err := provide(defaultScope); err != nil {
 			return fmt.Errorf("加载资源[%s]错误: %w", defaultScope, err)
 		}
@@ -29,7 +36,7 @@ func Boot(scopes ...string) func() error {
 			}
 		}
 		return nil
-	}
+	}, "log")
 }
 
 // Exist 判断scope实例是否挂载 (被Boot过) 且类型正确
@@ -64,6 +71,24 @@ func provide(scope string) error {
 	// 挂载实例
 	do.ProvideNamedValue(nil, iocPrefix+scope, instance)
 
+	// 注册就绪检查
+	kernel.RegisterHealthCheck("ndb:"+scope, func(ctx context.Context) error {
+		db, err := instance.DB()
+		if err != nil {
+			return err
+		}
+		return db.PingContext(ctx)
+	})
+
+	// 注册关闭钩子
+	kernel.OnClose("ndb:"+scope, func(context.Context) error {
+		db, err := instance.DB()
+		if err != nil {
+			return err
+		}
+		return db.Close()
+	})
+
 	return nil
 }
 
@@ -83,11 +83,11 @@ type Config struct {
 	InfoRecordTime time.Duration `mapstructure:"info_record_time"`
 	WarnRecordTime time.Duration `mapstructure:"warn_record_time"`
 
-	Addrs      []string `mapstructure:"addrs"`
+	Addrs      []string `mapstructure:"addrs" validate:"required,min=1,dive,required"`
 	ClientName string   `mapstructure:"client_name"`
-	DB         int      `mapstructure:"db"`
+	DB         int      `mapstructure:"db" validate:"gte=0"`
 
-	Protocol         int    `mapstructure:"protocol"`
+	Protocol         int    `mapstructure:"protocol" validate:"oneof=0 2 3"`
 	Username         string `mapstructure:"username"`
 	Password         string `mapstructure:"password"`
 	SentinelUsername string `mapstructure:"sentinel_username"`
@@ -129,7 +129,7 @@ type Config struct {
 
 	MaintNotificationsConfig MaintnotificationsConfig `mapstructure:"maint_notifications_config"`
 
-	Mode string `mapstructure:"mode"`
+	Mode string `mapstructure:"mode" validate:"oneof=single cluster failover"`
 }
 
 type MaintnotificationsConfig struct {
@@ -35,7 +35,7 @@ type Config struct {
 	InfoRecordTime time.Duration `mapstructure:"info_record_time"`
 	WarnRecordTime time.Duration `mapstructure:"warn_record_time"`
 
-	Timeout time.Duration `mapstructure:"timeout"`
+	Timeout time.Duration `mapstructure:"timeout" validate:"gte=0"`
 
 	// HTTP Client Transport配置
 	TLSHandshakeTimeout    time.Duration `mapstructure:"tls_handshake_timeout"`
@@ -55,7 +55,7 @@ type Config struct {
 	DialContextKeepAlive   time.Duration `mapstructure:"dial_context_keep_alive"`
 
 	// resty Retry配置
-	RetryCount       int           `mapstructure:"retry_count"`
+	RetryCount       int           `mapstructure:"retry_count" validate:"gte=0"`
 	RetryWaitTime    time.Duration `mapstructure:"retry_wait_time"`
 	RetryMaxWaitTime time.Duration `mapstructure:"retry_max_wait_time"`
 }
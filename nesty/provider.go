@@ -8,6 +8,7 @@ import (
 	"github.com/samber/do"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 )
 
@@ -17,8 +18,13 @@ const (
 )
 
 // Boot 预加载默认实例 同时加载指定实例列表
-func Boot(scopes ...string) func() error {
-	return func() error {
+// 引导步骤名为resty 依赖log
+func Boot(scopes ...string) kernel.Step {
+	kernel.RegisterConfig(defaultScope, DefaultConfig)
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope, func() error {
 		if err := provide(defaultScope); err != nil {
 			return fmt.Errorf("加载资源[%s]错误: %w", defaultScope, err)
 		}
@@ -28,7 +34,7 @@ func Boot(scopes ...string) func() error {
 			}
 		}
 		return nil
-	}
+	}, "log")
 }
 
 // Exist 判断scope实例是否挂载 (被Boot过) 且类型正确
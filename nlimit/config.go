@@ -0,0 +1,20 @@
+package nlimit
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+var DefaultConfig = Config{
+	Rate:   "100-S",
+	Driver: DriverMemory,
+	Redis:  "",
+	Prefix: "limiter",
+}
+
+type Config struct {
+	Rate   string `mapstructure:"rate" validate:"required"`             // Rate 限流速率 格式如 "10-S" "100-M" "1000-H"
+	Driver string `mapstructure:"driver" validate:"oneof=memory redis"` // Driver 存储驱动 memory/redis
+	Redis  string `mapstructure:"redis"`                                // Redis 驱动为redis时使用的nedis实例
+	Prefix string `mapstructure:"prefix"`                               // Prefix Redis Key 前缀
+}
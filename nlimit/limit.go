@@ -2,6 +2,7 @@ package nlimit
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/ulule/limiter/v3"
@@ -19,13 +20,24 @@ type Limiter interface {
 
 // limiterImpl 实现 Limiter 接口
 type limiterImpl struct {
-	instance *limiter.Limiter
+	instance atomic.Pointer[limiter.Limiter]
 }
 
 // New 创建一个新的限流器
 // rateStr: 限流速率字符串，格式如 "10-S" (每秒10次), "100-M" (每分钟100次), "1000-H" (每小时1000次)
 // opts: 配置选项
 func New(rateStr string, opts ...Option) (Limiter, error) {
+	instance, err := newInstance(rateStr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l := &limiterImpl{}
+	l.instance.Store(instance)
+	return l, nil
+}
+
+// newInstance 创建底层限流器实例
+func newInstance(rateStr string, opts ...Option) (*limiter.Limiter, error) {
 	// 解析速率
 	rate, err := limiter.NewRateFromFormatted(rateStr)
 	if err != nil {
@@ -54,11 +66,14 @@ func New(rateStr string, opts ...Option) (Limiter, error) {
 		store = memory.NewStore()
 	}
 
-	instance := limiter.New(store, rate)
-
-	return &limiterImpl{instance: instance}, nil
+	return limiter.New(store, rate), nil
 }
 
 func (l *limiterImpl) Allow(ctx context.Context, key string) (limiter.Context, error) {
-	return l.instance.Get(ctx, key)
+	return l.instance.Load().Get(ctx, key)
+}
+
+// reset 替换底层限流器实例 (内存存储的计数会随之重置)
+func (l *limiterImpl) reset(instance *limiter.Limiter) {
+	l.instance.Store(instance)
 }
@@ -1,7 +1,16 @@
 package nlimit
 
 import (
+	"fmt"
+
+	"github.com/jinzhu/copier"
 	"github.com/samber/do"
+	"github.com/spf13/viper"
+
+	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
+	"github.com/zjutjh/mygo/kit"
+	"github.com/zjutjh/mygo/nedis"
 )
 
 const (
@@ -9,18 +18,31 @@ const (
 	defaultScope = "limit"
 )
 
-// Boot 注册默认限流器 (内存模式, 默认 100 req/s)
-// 你可以在应用启动时根据配置覆盖这个默认值
-func Boot() func() error {
-	return func() error {
-		// 默认注册一个内存限流器，防止未配置时报错
-		l, err := New("100-S")
-		if err != nil {
-			return err
+// Boot 预加载默认实例 同时加载指定实例列表
+// 默认实例未配置config.yaml[limit]时使用默认配置 (内存模式, 100 req/s), 防止未配置时报错
+// 引导步骤名为limit 使用redis驱动时需在redis之后
+func Boot(scopes ...string) kernel.Step {
+	kernel.RegisterOptionalConfig(defaultScope, DefaultConfig)
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope, func() error {
+		if err := provide(defaultScope); err != nil {
+			return fmt.Errorf("加载资源[%s]错误: %w", defaultScope, err)
+		}
+		for _, scope := range scopes {
+			if err := provide(scope); err != nil {
+				return fmt.Errorf("加载资源[%s]错误: %w", scope, err)
+			}
 		}
-		do.ProvideNamedValue(nil, iocPrefix+defaultScope, l)
 		return nil
-	}
+	}).RunsAfter("redis")
+}
+
+// Exist 判断scope实例是否挂载 (被Boot过) 且类型正确
+func Exist(scope string) bool {
+	_, err := do.InvokeNamed[Limiter](nil, iocPrefix+scope)
+	return err == nil
 }
 
 // Pick 获取指定 scope 的限流器
@@ -30,3 +52,77 @@ func Pick(scope string) Limiter {
 	}
 	return do.MustInvokeNamed[Limiter](nil, iocPrefix+scope)
 }
+
+// provide 提供指定scope实例
+func provide(scope string) error {
+	// 获取配置
+	conf, err := getConf(scope)
+	if err != nil {
+		return err
+	}
+
+	// 初始化实例
+	instance, err := newInstance(conf.Rate, confOptions(conf)...)
+	if err != nil {
+		return fmt.Errorf("初始化限流器实例错误: %w", err)
+	}
+	l := &limiterImpl{}
+	l.instance.Store(instance)
+
+	// 挂载实例
+	do.ProvideNamedValue[Limiter](nil, iocPrefix+scope, l)
+
+	// 配置变更时调整限流速率
+	config.OnChange("", scope, func(*viper.Viper) error {
+		conf, err := getConf(scope)
+		if err != nil {
+			return err
+		}
+		instance, err := newInstance(conf.Rate, confOptions(conf)...)
+		if err != nil {
+			return fmt.Errorf("重新初始化限流器实例错误: %w", err)
+		}
+		l.reset(instance)
+		return nil
+	})
+
+	return nil
+}
+
+// confOptions 将配置转换为限流器选项
+func confOptions(conf Config) []Option {
+	opts := []Option{WithPrefix(conf.Prefix)}
+	if conf.Driver == DriverRedis {
+		opts = append(opts, WithRedis(nedis.Pick(conf.Redis)))
+	}
+	return opts
+}
+
+// getConf 获取配置
+func getConf(scope string) (conf Config, err error) {
+	// 初始化默认配置
+	conf, err = defaultConfig()
+	if err != nil {
+		return conf, err
+	}
+	// 判断 scope 配置是否存在
+	cfg := config.Pick()
+	if !cfg.IsSet(scope) {
+		if scope == defaultScope {
+			return conf, nil
+		}
+		return conf, fmt.Errorf("%w: 配置config.yaml[%s]不存在", kit.ErrNotFound, scope)
+	}
+	// 解析 config.yaml[{scope}]
+	err = cfg.UnmarshalKey(scope, &conf)
+	if err != nil {
+		return conf, fmt.Errorf("%w: 解析config.yaml[%s]错误: %w", kit.ErrDataUnmarshal, scope, err)
+	}
+	return conf, nil
+}
+
+// defaultConfig 获取默认配置
+func defaultConfig() (conf Config, err error) {
+	err = copier.CopyWithOption(&conf, &DefaultConfig, copier.Option{DeepCopy: true})
+	return conf, err
+}
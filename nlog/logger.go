@@ -12,21 +12,26 @@ import (
 
 	"github.com/zjutjh/mygo/config"
 	"github.com/zjutjh/mygo/feishu"
+	"github.com/zjutjh/mygo/foundation/kernel"
 )
 
 func New(conf Config) *logrus.Logger {
 	logger := logrus.New()
 
-	// 设置Output
-	os.OpenFile(conf.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	logger.SetOutput(&lumberjack.Logger{
-		Filename:   conf.Filename,
-		MaxSize:    conf.MaxSize,
-		MaxAge:     conf.MaxAge,
-		MaxBackups: conf.MaxBackups,
-		LocalTime:  conf.LocalTime,
-		Compress:   conf.Compress,
-	})
+	// 设置Output 标准输出跟随框架提示信息的输出位置 (如--output=json时为标准错误)
+	if conf.Filename == "/dev/stdout" {
+		logger.SetOutput(kernel.Output())
+	} else {
+		os.OpenFile(conf.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		logger.SetOutput(&lumberjack.Logger{
+			Filename:   conf.Filename,
+			MaxSize:    conf.MaxSize,
+			MaxAge:     conf.MaxAge,
+			MaxBackups: conf.MaxBackups,
+			LocalTime:  conf.LocalTime,
+			Compress:   conf.Compress,
+		})
+	}
 
 	// 设置Formatter
 	logger.SetFormatter(&logrus.JSONFormatter{
@@ -12,6 +12,7 @@ import (
 
 	"github.com/zjutjh/mygo/config"
 	"github.com/zjutjh/mygo/feishu"
+	"github.com/zjutjh/mygo/foundation/kernel"
 )
 
 func New(conf Config) *logrus.Logger {
@@ -19,7 +20,7 @@ func New(conf Config) *logrus.Logger {
 
 	// 设置Output
 	if conf.Filename == "/dev/stdout" {
-		logger.SetOutput(os.Stdout)
+		logger.SetOutput(kernel.Output())
 	} else {
 		os.OpenFile(conf.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 		logger.SetOutput(&lumberjack.Logger{
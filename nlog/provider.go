@@ -1,13 +1,19 @@
 package nlog
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"sync"
 
 	"github.com/jinzhu/copier"
 	"github.com/samber/do"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 )
 
@@ -17,8 +23,13 @@ const (
 )
 
 // Boot 预加载默认实例 同时加载指定实例列表
-func Boot(scopes ...string) func() error {
-	return func() error {
+// 引导步骤名为log 依赖feishu
+func Boot(scopes ...string) kernel.Step {
+	kernel.RegisterConfig(defaultScope, DefaultConfig)
+	for _, scope := range scopes {
+		kernel.RegisterConfig(scope, DefaultConfig)
+	}
+	return kernel.NewStep(defaultScope, func() error {
 		if err := provide(defaultScope); err != nil {
 			return fmt.Errorf("加载资源[%s]错误: %w", defaultScope, err)
 		}
@@ -28,7 +39,7 @@ func Boot(scopes ...string) func() error {
 			}
 		}
 		return nil
-	}
+	}, "feishu")
 }
 
 // Exist 判断scope实例是否挂载 (被Boot过) 且类型正确
@@ -56,10 +67,99 @@ func provide(scope string) error {
 
 	// 初始化实例
 	instance := New(conf)
+	out := &output{w: instance.Out}
 
 	// 挂载实例
 	do.ProvideNamedValue(nil, iocPrefix+scope, instance)
 
+	// 注册关闭钩子 日志在其他资源关闭后最后关闭
+	kernel.OnCloseLast("nlog:"+scope, func(context.Context) error {
+		return out.close()
+	})
+
+	// 注册运行时信号处理
+	// SIGHUP 关闭当前日志文件句柄 下次写入时按原路径重新打开 (配合logrotate移走文件)
+	kernel.OnSignal(kernel.SignalReload, "nlog:"+scope, out.close)
+	// SIGUSR2 在debug与配置等级之间切换
+	kernel.OnSignal(kernel.SignalToggleDebug, "nlog:"+scope, func() error {
+		return toggleDebug(instance, scope)
+	})
+	// SIGUSR1 由默认实例输出goroutine堆栈与运行时统计
+	if scope == defaultScope {
+		kernel.OnSignal(kernel.SignalDiagnose, "nlog", func() error {
+			instance.WithFields(kernel.RuntimeStats()).
+				WithField("stacks", kernel.GoroutineStacks()).
+				Warn("运行时诊断信息")
+			return nil
+		})
+	}
+
+	// 配置变更时原地调整实例
+	config.OnChange("", scope, func(*viper.Viper) error {
+		return reload(instance, out, scope)
+	})
+
+	return nil
+}
+
+// reload 按最新配置原地调整实例 已持有实例的使用方无需重新获取
+func reload(logger *logrus.Logger, out *output, scope string) error {
+	conf, err := getConf(scope)
+	if err != nil {
+		return err
+	}
+	n := New(conf)
+	logger.ReplaceHooks(n.Hooks)
+	logger.SetLevel(n.GetLevel())
+	return out.swap(logger, n.Out)
+}
+
+// output 实例当前的输出
+// logrus.Logger.Out仅可在其内部锁保护下读取 因此单独记录 替换输出时经SetOutput加锁
+type output struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// swap 替换实例的输出并关闭旧的日志文件句柄
+// SetOutput返回后不再有写入使用旧输出 可安全关闭
+func (o *output) swap(logger *logrus.Logger, w io.Writer) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	old := o.w
+	logger.SetOutput(w)
+	o.w = w
+	if lw, ok := old.(*lumberjack.Logger); ok && old != w {
+		return lw.Close()
+	}
+	return nil
+}
+
+// close 关闭当前的日志文件句柄 下次写入时按原路径重新打开
+func (o *output) close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if w, ok := o.w.(*lumberjack.Logger); ok {
+		return w.Close()
+	}
+	return nil
+}
+
+// toggleDebug 当前等级低于debug时切换为debug 否则恢复为配置等级 (配置等级即为debug时切换为info)
+func toggleDebug(logger *logrus.Logger, scope string) error {
+	if logger.GetLevel() < logrus.DebugLevel {
+		logger.SetLevel(logrus.DebugLevel)
+		return nil
+	}
+	conf, err := getConf(scope)
+	if err != nil {
+		return err
+	}
+	level := conf.Level
+	if level >= logrus.DebugLevel {
+		level = logrus.InfoLevel
+	}
+	logger.SetLevel(level)
 	return nil
 }
 
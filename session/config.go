@@ -24,9 +24,9 @@ var DefaultConfig = Config{
 }
 
 type Config struct {
-	Driver string `mapstructure:"driver"`
-	Name   string `mapstructure:"name"`
-	Secret string `mapstructure:"secret"`
+	Driver string `mapstructure:"driver" validate:"oneof=memory redis"`
+	Name   string `mapstructure:"name" validate:"required"`
+	Secret string `mapstructure:"secret" validate:"required"`
 	Redis  string `mapstructure:"redis"`
 
 	// Cookie 相关选项
@@ -1,6 +1,8 @@
 package redis
 
 import (
+	"context"
+
 	"github.com/gin-contrib/sessions"
 	"github.com/redis/go-redis/v9"
 	"github.com/zjutjh/mygo/session/redistore"
@@ -8,6 +10,8 @@ import (
 
 type Store interface {
 	sessions.Store
+	// Check 检查底层redis连接是否可用
+	Check(ctx context.Context) error
 }
 
 func NewStore(client redis.UniversalClient, keyPairs ...[]byte) (Store, error) {
@@ -233,6 +233,12 @@ func NewRediStore(client redis.UniversalClient, keyPairs ...[]byte) (*RediStore,
 	return rs, err
 }
 
+// Check checks that the underlying redis server is reachable.
+// It is intended to be used as a readiness probe.
+func (s *RediStore) Check(ctx context.Context) error {
+	return s.ping(ctx)
+}
+
 // Close closes the underlying redis.UniversalClient
 func (s *RediStore) Close() error {
 	return s.Client.Close()
@@ -9,6 +9,7 @@ import (
 	"github.com/jinzhu/copier"
 
 	"github.com/zjutjh/mygo/config"
+	"github.com/zjutjh/mygo/foundation/kernel"
 	"github.com/zjutjh/mygo/kit"
 	"github.com/zjutjh/mygo/nedis"
 	"github.com/zjutjh/mygo/session/redis"
@@ -18,22 +19,50 @@ const defaultConfigKey = "session"
 
 const IdentityKey = "_session_identity_"
 
+func init() {
+	kernel.RegisterOptionalConfig(defaultConfigKey, DefaultConfig)
+}
+
+// Boot 注册默认实例与指定实例列表的配置结构 并在引导时检查其配置与依赖的redis实例
+// 未通过Boot声明的实例仍可直接Pick 但不参与引导时的配置校验
+// 引导步骤名为session 使用redis驱动时需在redis之后
+func Boot(keys ...string) kernel.Step {
+	kernel.RegisterOptionalConfig(defaultConfigKey, DefaultConfig)
+	for _, key := range keys {
+		kernel.RegisterConfig(key, DefaultConfig)
+	}
+	return kernel.NewStep(defaultConfigKey, func() error {
+		checks := keys
+		if config.Pick().IsSet(defaultConfigKey) {
+			checks = append([]string{defaultConfigKey}, keys...)
+		}
+		for _, key := range checks {
+			conf, err := getConf(key)
+			if err != nil {
+				return fmt.Errorf("加载资源[%s]错误: %w", key, err)
+			}
+			if conf.Driver != DriverRedis {
+				continue
+			}
+			scope := conf.Redis
+			if scope == "" {
+				scope = "redis" // nedis默认实例scope
+			}
+			if !nedis.Exist(scope) {
+				return fmt.Errorf("加载资源[%s]错误: 依赖的redis实例[%s]未引导", key, scope)
+			}
+		}
+		return nil
+	}).RunsAfter("redis")
+}
+
 // Pick 获取指定实例
 func Pick(keys ...string) gin.HandlerFunc {
 	key := defaultConfigKey
 	if len(keys) != 0 && keys[0] != "" {
 		key = keys[0]
 	}
-	conf := Config{}
-	err := copier.Copy(&conf, DefaultConfig)
-	if err != nil {
-		panic(err)
-	}
-	app := config.Pick()
-	if !app.IsSet(key) {
-		panic(kit.ErrNotFound)
-	}
-	err = app.UnmarshalKey(key, &conf)
+	conf, err := getConf(key)
 	if err != nil {
 		panic(err)
 	}
@@ -42,12 +71,14 @@ func Pick(keys ...string) gin.HandlerFunc {
 	keyPairs := []byte(conf.Secret)
 	switch conf.Driver {
 	case DriverRedis:
-		var err error
 		rdb := nedis.Pick(conf.Redis)
-		store, err = redis.NewStore(rdb, keyPairs)
+		rs, err := redis.NewStore(rdb, keyPairs)
 		if err != nil {
 			panic(err)
 		}
+		// 注册就绪检查 底层连接与nedis共享 由nedis负责关闭
+		kernel.RegisterHealthCheck("session:"+key, rs.Check)
+		store = rs
 	case DriverMemory:
 		store = memstore.NewStore(keyPairs)
 	default:
@@ -94,3 +125,23 @@ func DeleteIdentity(ctx *gin.Context) error {
 	session.Delete(IdentityKey)
 	return session.Save()
 }
+
+// getConf 获取config.yaml[key]配置
+func getConf(key string) (conf Config, err error) {
+	// 初始化默认配置
+	err = copier.Copy(&conf, DefaultConfig)
+	if err != nil {
+		return conf, err
+	}
+	// 判断 key 配置是否存在
+	cfg := config.Pick()
+	if !cfg.IsSet(key) {
+		return conf, fmt.Errorf("%w: 配置config.yaml[%s]不存在", kit.ErrNotFound, key)
+	}
+	// 解析 config.yaml[{key}]
+	err = cfg.UnmarshalKey(key, &conf)
+	if err != nil {
+		return conf, fmt.Errorf("%w: 解析config.yaml[%s]错误: %w", kit.ErrDataUnmarshal, key, err)
+	}
+	return conf, nil
+}
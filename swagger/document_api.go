@@ -136,165 +136,168 @@ func parseNode(method string, node reflect.Value, parentPath string, middlewareM
 }
 
 func DocumentHandler(engine *gin.Engine) gin.HandlerFunc {
-	var selfFuncName string
-
-	handler := func(ctx *gin.Context) {
-		// openapi版本
-		// 项目基本信息
-		// 项目服务实例信息
-		// projectKey := "API Document"
-		servers := []Server{}
-		_ = config.Pick().UnmarshalKey("openapi.servers", &servers)
-		schemaReg := newSchemaRegistry()
-		openapi := OpenAPI{
-			Openapi: "3.1.0",
-			Info: Info{
-				Title:       fmt.Sprintf("接口[%s]文档", config.AppName()),
-				Summary:     fmt.Sprintf("接口[%s]文档", config.AppName()),
-				Description: fmt.Sprintf("接口[%s]文档", config.AppName()),
-				Version:     "",
-			},
-			Servers: servers,
-			Paths:   map[string]PathItem{},
-			Components: Components{
-				Schemas:         schemaReg.schemas,
-				Examples:        map[string]ExampleObject{},
-				SecuritySchemes: map[string]SecurityScheme{},
-			},
-		}
+	return func(ctx *gin.Context) {
+		openapi := Document(engine)
 
-		// 确定group规则
-		groupKeyStart := 2
-		groupKeyEnd := 3
-		if config.Pick().IsSet("openapi.group_key_start") {
-			groupKeyStart = config.Pick().GetInt("openapi.group_key_start")
-		}
-		if config.Pick().IsSet("openapi.group_key_end") {
-			groupKeyEnd = config.Pick().GetInt("openapi.group_key_end")
+		format := ctx.Query("format")
+		if format == "yaml" {
+			ctx.Header("Content-Disposition", "attachment; filename=swagger.yaml")
+			ctx.YAML(http.StatusOK, openapi)
+		} else {
+			ctx.JSON(http.StatusOK, openapi)
 		}
+	}
+}
 
-		middlewareMap, err := gatherMiddlewares(engine)
-		if err != nil {
-			Output("无法获取路由中间件信息: %s\n", err.Error())
-		}
+// Document 以engine中已注册CM的路由生成OpenAPI文档 (与DocumentHandler输出一致)
+// 框架内置路由 (探针、pprof、文档等) 未注册CM时不输出提示
+func Document(engine *gin.Engine) OpenAPI {
+	// openapi版本
+	// 项目基本信息
+	// 项目服务实例信息
+	// projectKey := "API Document"
+	servers := []Server{}
+	_ = config.Pick().UnmarshalKey("openapi.servers", &servers)
+	schemaReg := newSchemaRegistry()
+	openapi := OpenAPI{
+		Openapi: "3.1.0",
+		Info: Info{
+			Title:       fmt.Sprintf("接口[%s]文档", config.AppName()),
+			Summary:     fmt.Sprintf("接口[%s]文档", config.AppName()),
+			Description: fmt.Sprintf("接口[%s]文档", config.AppName()),
+			Version:     "",
+		},
+		Servers: servers,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas:         schemaReg.schemas,
+			Examples:        map[string]ExampleObject{},
+			SecuritySchemes: map[string]SecurityScheme{},
+		},
+	}
 
-		// 取出所有接口
-		routes := engine.Routes()
-		for _, route := range routes {
-			api, exist := CM[route.Handler]
-			if !exist {
-				if route.Handler != selfFuncName {
-					Output("发现未注册CM的接口[%s]\n", route.Path)
-				}
-				continue
-			}
+	// 确定group规则
+	groupKeyStart := 2
+	groupKeyEnd := 3
+	if config.Pick().IsSet("openapi.group_key_start") {
+		groupKeyStart = config.Pick().GetInt("openapi.group_key_start")
+	}
+	if config.Pick().IsSet("openapi.group_key_end") {
+		groupKeyEnd = config.Pick().GetInt("openapi.group_key_end")
+	}
 
-			// 获取接口group、path、method
-			group := safeGroup(route.Path, groupKeyStart, groupKeyEnd)
-			pathSlice := strings.Split(route.Path, "/")
-			for i, s := range pathSlice {
-				if s != "" && (s[0] == ':' || s[0] == '*') {
-					pathSlice[i] = "{" + s[1:] + "}"
-				}
-			}
-			path := strings.Join(pathSlice, "/")
-			method := strings.ToUpper(route.Method)
-
-			// 获取api的type、value反射
-			t := reflect.TypeOf(api)
-			// v := reflect.ValueOf(api)
-
-			// 获取接口name、description、summary
-			d, ok := t.FieldByName("Info")
-			name := "API名称"
-			desc := "API描述"
-			if ok {
-				name = d.Tag.Get("name")
-				desc = d.Tag.Get("desc")
+	middlewareMap, err := gatherMiddlewares(engine)
+	if err != nil {
+		Output("无法获取路由中间件信息: %s\n", err.Error())
+	}
+
+	// 取出所有接口
+	routes := engine.Routes()
+	for _, route := range routes {
+		api, exist := CM[route.Handler]
+		if !exist {
+			if !isBuiltin(route.Handler) {
+				Output("发现未注册CM的接口[%s]\n", route.Path)
 			}
+			continue
+		}
 
-			// 获取接口request query、header、uri、cookie
-			parameters := ParseApiStandRequestParameters(t, "Query", "form", "query")
-			parameters = append(parameters, ParseApiStandRequestParameters(t, "Header", "header", "header")...)
-			parameters = append(parameters, ParseApiStandRequestParameters(t, "Uri", "uri", "path")...)
+		// 获取接口group、path、method
+		group := safeGroup(route.Path, groupKeyStart, groupKeyEnd)
+		pathSlice := strings.Split(route.Path, "/")
+		for i, s := range pathSlice {
+			if s != "" && (s[0] == ':' || s[0] == '*') {
+				pathSlice[i] = "{" + s[1:] + "}"
+			}
+		}
+		path := strings.Join(pathSlice, "/")
+		method := strings.ToUpper(route.Method)
+
+		// 获取api的type、value反射
+		t := reflect.TypeOf(api)
+		// v := reflect.ValueOf(api)
+
+		// 获取接口name、description、summary
+		d, ok := t.FieldByName("Info")
+		name := "API名称"
+		desc := "API描述"
+		if ok {
+			name = d.Tag.Get("name")
+			desc = d.Tag.Get("desc")
+		}
 
-			// 获取接口request body
-			request := ParseApiStandRequestBody(t, schemaReg)
+		// 获取接口request query、header、uri、cookie
+		parameters := ParseApiStandRequestParameters(t, "Query", "form", "query")
+		parameters = append(parameters, ParseApiStandRequestParameters(t, "Header", "header", "header")...)
+		parameters = append(parameters, ParseApiStandRequestParameters(t, "Uri", "uri", "path")...)
 
-			// 获取所有中间件（不包含末端的处理器）
-			middlewares := middlewareMap.get(method, route.Path)
-			// 获取所有状态码
-			fullChain := append(middlewares, route.Handler)
-			businessStatusCodes := getAllBusinessStatusCodes(fullChain...)
-			registerCommonResponseExamples(openapi.Components.Examples, businessStatusCodes)
+		// 获取接口request body
+		request := ParseApiStandRequestBody(t, schemaReg)
 
-			// 按标准模式获取接口response
-			responses := map[string]Response{
-				"200": ParseApiStandResponse(t, businessStatusCodes, schemaReg),
-			}
-			if failureResponse, exist := GenerateApiFailureResponse(businessStatusCodes); exist {
-				responses["default"] = failureResponse
-			}
+		// 获取所有中间件（不包含末端的处理器）
+		middlewares := middlewareMap.get(method, route.Path)
+		// 获取所有状态码
+		fullChain := append(middlewares, route.Handler)
+		businessStatusCodes := getAllBusinessStatusCodes(fullChain...)
+		registerCommonResponseExamples(openapi.Components.Examples, businessStatusCodes)
 
-			// 组装operation
-			operation := &Operation{
-				Tags:        []string{group},
-				Summary:     name,
-				Description: desc,
-				Parameters:  parameters,
-				RequestBody: request,
-				Responses:   responses,
-				Servers:     nil,
-			}
-			// 鉴权中间件解析
-			securitySchemes := parseAuthenticationMiddleware(middlewares)
-			operation.Security = make([]SecurityItem, 0, len(securitySchemes))
-			for _, securityScheme := range securitySchemes {
-				if _, ok := openapi.Components.SecuritySchemes[securityScheme.key]; !ok {
-					openapi.Components.SecuritySchemes[securityScheme.key] = securityScheme.scheme
-				}
-				operation.Security = append(operation.Security, SecurityItem{
-					securityScheme.key: []string{},
-				})
-			}
+		// 按标准模式获取接口response
+		responses := map[string]Response{
+			"200": ParseApiStandResponse(t, businessStatusCodes, schemaReg),
+		}
+		if failureResponse, exist := GenerateApiFailureResponse(businessStatusCodes); exist {
+			responses["default"] = failureResponse
+		}
 
-			// 放入pathItem
-			pathItem, exist := openapi.Paths[path]
-			if !exist {
-				pathItem = PathItem{}
-			}
-			switch method {
-			case "GET":
-				pathItem.Get = operation
-			case "PUT":
-				pathItem.Put = operation
-			case "POST":
-				pathItem.Post = operation
-			case "DELETE":
-				pathItem.Delete = operation
-			case "OPTIONS":
-				pathItem.Options = operation
-			case "HEAD":
-				pathItem.Head = operation
-			case "PATCH":
-				pathItem.Patch = operation
-			case "TRACE":
-				pathItem.Trace = operation
+		// 组装operation
+		operation := &Operation{
+			Tags:        []string{group},
+			Summary:     name,
+			Description: desc,
+			Parameters:  parameters,
+			RequestBody: request,
+			Responses:   responses,
+			Servers:     nil,
+		}
+		// 鉴权中间件解析
+		securitySchemes := parseAuthenticationMiddleware(middlewares)
+		operation.Security = make([]SecurityItem, 0, len(securitySchemes))
+		for _, securityScheme := range securitySchemes {
+			if _, ok := openapi.Components.SecuritySchemes[securityScheme.key]; !ok {
+				openapi.Components.SecuritySchemes[securityScheme.key] = securityScheme.scheme
 			}
-			openapi.Paths[path] = pathItem
+			operation.Security = append(operation.Security, SecurityItem{
+				securityScheme.key: []string{},
+			})
 		}
 
-		format := ctx.Query("format")
-		if format == "yaml" {
-			ctx.Header("Content-Disposition", "attachment; filename=swagger.yaml")
-			ctx.YAML(http.StatusOK, openapi)
-		} else {
-			ctx.JSON(http.StatusOK, openapi)
+		// 放入pathItem
+		pathItem, exist := openapi.Paths[path]
+		if !exist {
+			pathItem = PathItem{}
+		}
+		switch method {
+		case "GET":
+			pathItem.Get = operation
+		case "PUT":
+			pathItem.Put = operation
+		case "POST":
+			pathItem.Post = operation
+		case "DELETE":
+			pathItem.Delete = operation
+		case "OPTIONS":
+			pathItem.Options = operation
+		case "HEAD":
+			pathItem.Head = operation
+		case "PATCH":
+			pathItem.Patch = operation
+		case "TRACE":
+			pathItem.Trace = operation
 		}
+		openapi.Paths[path] = pathItem
 	}
 
-	selfFuncName = runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
-	return handler
+	return openapi
 }
 
 func parseAuthenticationMiddleware(middlewareNames []string) []*securitySchemeInfo {
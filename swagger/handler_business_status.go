@@ -33,12 +33,20 @@ func MustRegisterBusinessStatusCodes(funcName string, codes []kit.Code) {
 }
 
 func getAllBusinessStatusCodes(handerNames ...string) []kit.Code {
+	codes, registered := collectBusinessStatusCodes(handerNames...)
+	if !registered && len(handerNames) > 0 {
+		Output("发现未注册业务状态码的处理器[%s]\n", handerNames[len(handerNames)-1])
+	}
+	return codes
+}
+
+// collectBusinessStatusCodes 汇总处理链上注册的业务状态码 registered表示末端处理器是否注册了业务状态码
+func collectBusinessStatusCodes(handerNames ...string) (codes []kit.Code, registered bool) {
 	allCodes := map[kit.Code]struct{}{}
 	for i, name := range handerNames {
 		codes, ok := statusCodeMap[name]
-		if !ok && i == len(handerNames)-1 {
-			Output("发现未注册业务状态码的处理器[%s]\n", name)
-			continue
+		if i == len(handerNames)-1 {
+			registered = ok
 		}
 		for _, code := range codes {
 			allCodes[code] = struct{}{}
@@ -51,5 +59,5 @@ func getAllBusinessStatusCodes(handerNames ...string) []kit.Code {
 	slices.SortFunc(ans, func(a, b kit.Code) int {
 		return int(a.Code) - int(b.Code)
 	})
-	return ans
+	return ans, registered
 }
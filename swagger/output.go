@@ -1,7 +1,19 @@
 package swagger
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// output 文档生成过程中提示信息的输出位置
+var output io.Writer = os.Stdout
+
+// SetOutput 设置文档生成过程中提示信息的输出位置 默认为标准输出
+func SetOutput(w io.Writer) {
+	output = w
+}
 
 func Output(format string, a ...any) {
-	fmt.Printf(format, a...)
+	fmt.Fprintf(output, format, a...)
 }
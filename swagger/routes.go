@@ -0,0 +1,77 @@
+package swagger
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteInfo 路由信息
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Middlewares []string `json:"middlewares"`
+	Handler     string   `json:"handler"`
+	// Documented 处理器是否已注册到CM
+	Documented bool `json:"documented"`
+	// Builtin 框架内置路由 (探针、pprof、文档等) 不要求注册CM
+	Builtin bool `json:"builtin"`
+	// Codes 处理链上注册的业务状态码
+	Codes []BusinessCode `json:"codes"`
+}
+
+// BusinessCode 业务状态码
+type BusinessCode struct {
+	Code    int64  `json:"code"`
+	Message string `json:"message"`
+}
+
+// builtinPrefixes 框架内置处理器所在包
+var builtinPrefixes = []string{"github.com/zjutjh/mygo/", "github.com/gin-contrib/pprof"}
+
+// isBuiltin 是否为框架内置处理器
+func isBuiltin(handler string) bool {
+	for _, prefix := range builtinPrefixes {
+		if strings.HasPrefix(handler, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Routes 汇总engine中的全部路由 按路径与方法排序
+func Routes(engine *gin.Engine) ([]RouteInfo, error) {
+	middlewareMap, err := gatherMiddlewares(engine)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := engine.Routes()
+	list := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		middlewares := middlewareMap.get(route.Method, route.Path)
+		codes, _ := collectBusinessStatusCodes(append(append([]string(nil), middlewares...), route.Handler)...)
+		_, documented := CM[route.Handler]
+		info := RouteInfo{
+			Method:      route.Method,
+			Path:        route.Path,
+			Middlewares: append([]string{}, middlewares...),
+			Handler:     route.Handler,
+			Documented:  documented,
+			Builtin:     isBuiltin(route.Handler),
+			Codes:       make([]BusinessCode, 0, len(codes)),
+		}
+		for _, c := range codes {
+			info.Codes = append(info.Codes, BusinessCode{Code: c.Code, Message: c.Message})
+		}
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path != list[j].Path {
+			return list[i].Path < list[j].Path
+		}
+		return list[i].Method < list[j].Method
+	})
+	return list, nil
+}
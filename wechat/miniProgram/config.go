@@ -47,7 +47,7 @@ var DefaultConfig = Config{
 }
 
 type Config struct {
-	AppID  string `mapstructure:"app_id"`
+	AppID  string `mapstructure:"app_id" validate:"required"`
 	Secret string `mapstructure:"secret"`
 
 	AppKey  string `mapstructure:"app_key"`
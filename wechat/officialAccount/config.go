@@ -50,7 +50,7 @@ var DefaultConfig = Config{
 }
 
 type Config struct {
-	AppID           string `mapstructure:"app_id"`
+	AppID           string `mapstructure:"app_id" validate:"required"`
 	Secret          string `mapstructure:"secret"`
 	Token           string `mapstructure:"token"`
 	AESKey          string `mapstructure:"aes_key"`